@@ -0,0 +1,97 @@
+package byte
+
+import (
+	"bytes"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultBufferClasses 是 NewBufferPool 未指定容量分级时使用的分级
+var DefaultBufferClasses = []int{1 << 10, 4 << 10, 16 << 10, 64 << 10, 256 << 10}
+
+// DefaultMaxRetained 是 NewBufferPool 未指定时缓存的缓冲区容量上限
+const DefaultMaxRetained = 1 << 20
+
+// BufferPool 是基于 sync.Pool 的 bytes.Buffer 池。
+// 缓冲区按容量分级存放，Get 从能满足容量要求的最小一级取出，
+// 容量超过上限的缓冲区在 Put 时直接丢弃，避免偶尔的大缓冲区长期占用内存。
+// BufferPool 可以被多个 goroutine 同时使用。
+type BufferPool struct {
+	classes     []int // 每一级缓冲区的最小容量，升序排列
+	pools       []sync.Pool
+	maxRetained int
+
+	hits, misses, oversize atomic.Uint64
+}
+
+// PoolStats 是 BufferPool 的统计数据
+type PoolStats struct {
+	Hits     uint64 // Get 从池中取到缓冲区的次数
+	Misses   uint64 // Get 新分配缓冲区的次数
+	Oversize uint64 // Put 因容量超过上限而丢弃缓冲区的次数
+}
+
+// NewBufferPool 创建缓冲区池。maxRetained 是缓存的缓冲区容量上限，<= 0 时使用 DefaultMaxRetained；
+// classes 是各级的最小容量，为空时使用 DefaultBufferClasses。
+func NewBufferPool(maxRetained int, classes ...int) *BufferPool {
+	if maxRetained <= 0 {
+		maxRetained = DefaultMaxRetained
+	}
+	if len(classes) == 0 {
+		classes = DefaultBufferClasses
+	}
+	classes = slices.Clone(classes)
+	slices.Sort(classes)
+	classes = slices.Compact(classes)
+	return &BufferPool{
+		classes:     classes,
+		pools:       make([]sync.Pool, len(classes)),
+		maxRetained: maxRetained,
+	}
+}
+
+// Get 返回一个空的缓冲区，容量至少为 size。
+func (p *BufferPool) Get(size int) *bytes.Buffer {
+	i, _ := slices.BinarySearch(p.classes, size)
+	if i < len(p.classes) {
+		if buf, ok := p.pools[i].Get().(*bytes.Buffer); ok {
+			p.hits.Add(1)
+			return buf
+		}
+		size = p.classes[i]
+	}
+	p.misses.Add(1)
+	buf := new(bytes.Buffer)
+	buf.Grow(size)
+	return buf
+}
+
+// Put 清空 buf 并放回池中，之后不能再使用 buf。
+// 容量超过上限的缓冲区会被丢弃，容量小于最小一级的缓冲区也不会缓存。
+func (p *BufferPool) Put(buf *bytes.Buffer) {
+	c := buf.Cap()
+	if c > p.maxRetained {
+		p.oversize.Add(1)
+		return
+	}
+	// 放入容量不小于该级最小容量的最高一级，保证从该级取出的缓冲区满足容量要求
+	i, found := slices.BinarySearch(p.classes, c)
+	if !found {
+		i--
+	}
+	if i < 0 {
+		return
+	}
+	buf.Reset()
+	p.pools[i].Put(buf)
+}
+
+// Stats 返回到目前为止的统计数据。
+func (p *BufferPool) Stats() PoolStats {
+	return PoolStats{
+		Hits:     p.hits.Load(),
+		Misses:   p.misses.Load(),
+		Oversize: p.oversize.Load(),
+	}
+}
@@ -0,0 +1,137 @@
+package byte
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+)
+
+func TestBufferPoolGet(t *testing.T) {
+	p := NewBufferPool(0)
+	for _, size := range []int{0, 1, 1 << 10, 1<<10 + 1, 100 << 10, 512 << 10} {
+		buf := p.Get(size)
+		if buf.Len() != 0 || buf.Cap() < size {
+			t.Errorf("Get(%d) returned Len %d, Cap %d", size, buf.Len(), buf.Cap())
+		}
+		p.Put(buf)
+	}
+}
+
+func TestBufferPoolReuse(t *testing.T) {
+	p := NewBufferPool(0, 64, 1024)
+	for i := 0; i < 100; i++ {
+		buf := p.Get(100)
+		if buf.Len() != 0 || buf.Cap() < 100 {
+			t.Fatalf("Get(100) returned Len %d, Cap %d", buf.Len(), buf.Cap())
+		}
+		buf.WriteString("some data")
+		p.Put(buf)
+	}
+	// sync.Pool 不保证缓存，但同一个 goroutine 连续 Put、Get 时绝大多数应该命中
+	stats := p.Stats()
+	if stats.Hits+stats.Misses != 100 || stats.Hits == 0 {
+		t.Errorf("Stats() = %+v, want 100 gets with some hits", stats)
+	}
+}
+
+func TestBufferPoolOversize(t *testing.T) {
+	p := NewBufferPool(4096, 1024)
+	big := new(bytes.Buffer)
+	big.Grow(8192)
+	p.Put(big)
+	p.Put(big)
+	if got := p.Stats().Oversize; got != 2 {
+		t.Errorf("Oversize = %d, want 2", got)
+	}
+	// 超过最高一级的请求直接分配，不影响池
+	if buf := p.Get(8192); buf.Cap() < 8192 {
+		t.Errorf("Get(8192) Cap = %d", buf.Cap())
+	}
+	if got := p.Stats().Misses; got != 1 {
+		t.Errorf("Misses = %d, want 1", got)
+	}
+}
+
+func TestBufferPoolSizeClasses(t *testing.T) {
+	p := NewBufferPool(0, 4096, 1024, 1024)
+	if len(p.classes) != 2 || p.classes[0] != 1024 || p.classes[1] != 4096 {
+		t.Fatalf("classes = %v, want [1024 4096]", p.classes)
+	}
+	// 容量 2000 的缓冲区只能放进 1024 一级，从 4096 一级取不到它
+	buf := new(bytes.Buffer)
+	buf.Grow(2000)
+	for i := 0; i < 10; i++ {
+		p.Put(buf)
+		if got := p.Get(3000); got == buf || got.Cap() < 3000 {
+			t.Fatalf("Get(3000) returned a buffer with Cap %d", got.Cap())
+		}
+		buf = p.Get(1000)
+	}
+}
+
+func TestBufferPoolConcurrent(t *testing.T) {
+	p := NewBufferPool(0)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				buf := p.Get(i % 5000)
+				buf.WriteString("concurrent")
+				if buf.String() != "concurrent" {
+					t.Errorf("buffer contains %q", buf.String())
+					return
+				}
+				p.Put(buf)
+			}
+		}()
+	}
+	wg.Wait()
+	if stats := p.Stats(); stats.Hits+stats.Misses != 8000 {
+		t.Errorf("Stats() = %+v, want 8000 gets", stats)
+	}
+}
+
+// BenchmarkBufferPool 与 BenchmarkBufferWrite 对比：每次请求都需要一个新的缓冲区时，
+// 直接创建会反复分配并扩容，池化后复用已有的缓冲区
+func BenchmarkBufferPool(b *testing.B) {
+	data := []byte("hello world")
+	const writes = 1000
+
+	b.Run("NewBuffer", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var buf bytes.Buffer
+			for j := 0; j < writes; j++ {
+				buf.Write(data)
+			}
+		}
+	})
+
+	b.Run("Pool", func(b *testing.B) {
+		p := NewBufferPool(0)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			buf := p.Get(writes * len(data))
+			for j := 0; j < writes; j++ {
+				buf.Write(data)
+			}
+			p.Put(buf)
+		}
+	})
+
+	b.Run("PoolParallel", func(b *testing.B) {
+		p := NewBufferPool(0)
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				buf := p.Get(writes * len(data))
+				for j := 0; j < writes; j++ {
+					buf.Write(data)
+				}
+				p.Put(buf)
+			}
+		})
+	})
+}
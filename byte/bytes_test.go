@@ -5,36 +5,11 @@ import (
 	"testing"
 )
 
-type ReplaceMode int
-
-const (
-	ReplaceAll ReplaceMode = iota
-	ReplaceFirst
-	ReplaceNone
-)
-
-// replaceWithMode 根据指定的替换模式替换字节切片中的内容。
-// 参数：
-//
-//	s - 原始字节切片
-//	old - 要被替换的字节切片
-//	new - 替换成的字节切片
-//	mode - 替换模式，支持 ReplaceAll、ReplaceFirst 和 ReplaceNone
-//
-// 返回值：
-//
-//	返回替换后的字节切片
+// replaceWithMode 是 Replace 提升为正式 API 之前的测试辅助函数，
+// 保留下来供已有测试和基准测试使用，只返回替换结果。
 func replaceWithMode(s, old, new []byte, mode ReplaceMode) []byte {
-	switch mode {
-	case ReplaceAll:
-		return bytes.ReplaceAll(s, old, new)
-	case ReplaceFirst:
-		return bytes.Replace(s, old, new, 1)
-	case ReplaceNone:
-		return bytes.Replace(s, old, new, 0)
-	default:
-		return s
-	}
+	out, _ := Replace(s, old, new, mode)
+	return out
 }
 
 func TestBufferWriteRead(t *testing.T) {
@@ -101,7 +76,7 @@ func TestBytesReplace(t *testing.T) {
 	input := []byte("hello world")
 	old := []byte("world")
 	new := []byte("go")
-	output := replaceWithMode(input, old, new, ReplaceAll)
+	output := replaceWithMode(input, old, new, ReplaceAll())
 	expected := []byte("hello go")
 	if !bytes.Equal(output, expected) {
 		t.Errorf("Bytes.Replace returned wrong data: got %q, want %q", output, expected)
@@ -111,7 +86,7 @@ func TestBytesReplace(t *testing.T) {
 	input = []byte("hello world")
 	old = []byte("o")
 	new = []byte("0")
-	output = replaceWithMode(input, old, new, ReplaceAll)
+	output = replaceWithMode(input, old, new, ReplaceAll())
 	expected = []byte("hell0 w0rld")
 	if !bytes.Equal(output, expected) {
 		t.Errorf("Bytes.ReplaceAll returned wrong data: got %q, want %q", output, expected)
@@ -124,10 +99,10 @@ func TestReplaceWithMode(t *testing.T) {
 		mode        ReplaceMode
 		expected    []byte
 	}{
-		{[]byte("go gopher go"), []byte("go"), []byte("Go"), ReplaceAll, []byte("Go Gopher Go")},
-		{[]byte("go gopher go"), []byte("go"), []byte("Go"), ReplaceFirst, []byte("Go gopher go")},
-		{[]byte("go gopher go"), []byte("go"), []byte("Go"), ReplaceNone, []byte("go gopher go")},
-		{[]byte("hello world"), []byte("x"), []byte("y"), ReplaceAll, []byte("hello world")},
+		{[]byte("go gopher go"), []byte("go"), []byte("Go"), ReplaceAll(), []byte("Go Gopher Go")},
+		{[]byte("go gopher go"), []byte("go"), []byte("Go"), ReplaceFirst(), []byte("Go gopher go")},
+		{[]byte("go gopher go"), []byte("go"), []byte("Go"), ReplaceNone(), []byte("go gopher go")},
+		{[]byte("hello world"), []byte("x"), []byte("y"), ReplaceAll(), []byte("hello world")},
 	}
 
 	for _, test := range tests {
@@ -226,6 +201,6 @@ func BenchmarkReplaceWithMode(b *testing.B) {
 	new := []byte("Go")
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		replaceWithMode(s, old, new, ReplaceAll)
+		replaceWithMode(s, old, new, ReplaceAll())
 	}
 }
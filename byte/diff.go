@@ -0,0 +1,296 @@
+package byte
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"compress/flate"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"slices"
+)
+
+var (
+	// ErrPatchCorrupt 表示补丁格式错误或已损坏
+	ErrPatchCorrupt = errors.New("byte: corrupt patch")
+	// ErrPatchBase 表示补丁不是基于传入的原始数据生成的
+	ErrPatchBase = errors.New("byte: patch does not match base data")
+)
+
+// patchMagic 是补丁的文件头，最后一个字节是格式版本
+var patchMagic = []byte("BDIF\x02")
+
+// maxPatchPrealloc 限制 Apply 按补丁声明的长度预先分配的内存，防止损坏的补丁导致大量分配
+const maxPatchPrealloc = 1 << 24
+
+// maxInflateRatio 是 DEFLATE 解压后与压缩前大小之比的上限（理论最大约 1032:1），
+// 新数据全部来自解压结果，声明的新数据长度超过这个比例的补丁一定是伪造或损坏的
+const maxInflateRatio = 1032
+
+// Patch 是 Diff 生成的序列化补丁，可以直接存储或传输。格式为：
+//
+//	"BDIF\x02"
+//	uvarint 原始数据长度、uvarint 新数据长度
+//	原始数据和新数据的 CRC-32（IEEE），各 4 字节大端序
+//	以上所有字节的 CRC-32（IEEE），4 字节大端序
+//	用 DEFLATE 压缩的控制记录序列，每条记录为
+//	    uvarint diffLen、uvarint extraLen、varint seek、
+//	    diffLen 个差值字节（新数据减原始数据）、extraLen 个新增字节
+//
+// 应用一条记录时，先把 diffLen 个差值与原始数据当前位置的字节相加输出，
+// 再原样输出 extraLen 个新增字节，最后把原始数据的位置移动 seek。
+type Patch []byte
+
+// Diff 生成把 old 变为 new 的补丁，算法与 bsdiff 相同：
+// 用原始数据的后缀数组查找近似匹配的区域，相似区域只保存逐字节的差值（大多为 0，便于压缩），
+// 其余部分作为新增数据保存。
+func Diff(old, new []byte) Patch {
+	var body bytes.Buffer
+	fw, _ := flate.NewWriter(&body, flate.BestCompression)
+	var rec [3 * binary.MaxVarintLen64]byte
+	emit := func(oldPos, newPos, diffLen, extraLen, seek int) {
+		n := binary.PutUvarint(rec[:], uint64(diffLen))
+		n += binary.PutUvarint(rec[n:], uint64(extraLen))
+		n += binary.PutVarint(rec[n:], int64(seek))
+		fw.Write(rec[:n])
+		diff := make([]byte, diffLen)
+		for i := range diff {
+			diff[i] = new[newPos+i] - old[oldPos+i]
+		}
+		fw.Write(diff)
+		fw.Write(new[newPos+diffLen : newPos+diffLen+extraLen])
+	}
+	bsdiff(old, new, emit)
+	fw.Close()
+
+	patch := append([]byte(nil), patchMagic...)
+	patch = binary.AppendUvarint(patch, uint64(len(old)))
+	patch = binary.AppendUvarint(patch, uint64(len(new)))
+	patch = binary.BigEndian.AppendUint32(patch, crc32.ChecksumIEEE(old))
+	patch = binary.BigEndian.AppendUint32(patch, crc32.ChecksumIEEE(new))
+	patch = binary.BigEndian.AppendUint32(patch, crc32.ChecksumIEEE(patch))
+	return append(patch, body.Bytes()...)
+}
+
+// Apply 把补丁应用到 old 上，返回新数据。
+// old 的长度或校验和与生成补丁时不同时返回 ErrPatchBase，补丁损坏时返回 ErrPatchCorrupt。
+func Apply(old []byte, patch Patch) ([]byte, error) {
+	if !bytes.HasPrefix(patch, patchMagic) {
+		return nil, ErrPatchCorrupt
+	}
+	p := patch[len(patchMagic):]
+	oldLen, n1 := binary.Uvarint(p)
+	if n1 <= 0 {
+		return nil, ErrPatchCorrupt
+	}
+	newLen, n2 := binary.Uvarint(p[n1:])
+	if n2 <= 0 || len(p) < n1+n2+12 {
+		return nil, ErrPatchCorrupt
+	}
+	header := len(patchMagic) + n1 + n2 + 8
+	p = p[n1+n2:]
+	oldSum, newSum := binary.BigEndian.Uint32(p), binary.BigEndian.Uint32(p[4:])
+	if binary.BigEndian.Uint32(p[8:]) != crc32.ChecksumIEEE(patch[:header]) ||
+		newLen > uint64(len(p)-12)*maxInflateRatio {
+		return nil, ErrPatchCorrupt
+	}
+	if oldLen != uint64(len(old)) || oldSum != crc32.ChecksumIEEE(old) {
+		return nil, ErrPatchBase
+	}
+
+	// bytes.Reader 实现了 io.ByteReader，flate 不会多读压缩流之后的数据
+	body := bytes.NewReader(p[12:])
+	out, err := bspatch(old, newLen, bufio.NewReader(flate.NewReader(body)))
+	if err != nil {
+		return nil, err
+	}
+	if body.Len() != 0 || crc32.ChecksumIEEE(out) != newSum {
+		return nil, ErrPatchCorrupt
+	}
+	return out, nil
+}
+
+// bspatch 按控制记录重建新数据，所有长度和位置都会检查，不会越界。
+// 差值和新增字节随读取分块追加，内存只随实际解压出的数据增长，不会按记录声明的长度一次分配。
+func bspatch(old []byte, newLen uint64, r *bufio.Reader) ([]byte, error) {
+	out := bytes.NewBuffer(make([]byte, 0, min(newLen, maxPatchPrealloc)))
+	oldPos := int64(0)
+	for uint64(out.Len()) < newLen {
+		diffLen, err1 := binary.ReadUvarint(r)
+		extraLen, err2 := binary.ReadUvarint(r)
+		seek, err3 := binary.ReadVarint(r)
+		if err := errors.Join(err1, err2, err3); err != nil {
+			return nil, ErrPatchCorrupt
+		}
+		rest := newLen - uint64(out.Len())
+		if diffLen > rest || extraLen > rest-diffLen ||
+			oldPos < 0 || uint64(oldPos)+diffLen > uint64(len(old)) {
+			return nil, ErrPatchCorrupt
+		}
+
+		start := out.Len()
+		if _, err := io.CopyN(out, r, int64(diffLen)); err != nil {
+			return nil, ErrPatchCorrupt
+		}
+		diff := out.Bytes()[start:]
+		for i := range diff {
+			diff[i] += old[oldPos+int64(i)]
+		}
+		if _, err := io.CopyN(out, r, int64(extraLen)); err != nil {
+			return nil, ErrPatchCorrupt
+		}
+		oldPos += int64(diffLen) + seek
+	}
+	// 记录之后不应该还有数据
+	if _, err := r.ReadByte(); err != io.EOF {
+		return nil, ErrPatchCorrupt
+	}
+	return out.Bytes(), nil
+}
+
+// bsdiff 是 bsdiff 4 的差异查找过程，对每条控制记录调用 emit：
+// 从原始数据 oldPos、新数据 newPos 开始的 diffLen 字节保存差值，之后 extraLen 字节原样保存，
+// 然后原始数据的位置移动 seek。
+func bsdiff(old, new []byte, emit func(oldPos, newPos, diffLen, extraLen, seek int)) {
+	sa := suffixArray(old)
+	var scan, length, pos, lastScan, lastPos, lastOffset int
+	for scan < len(new) {
+		oldScore := 0
+		scan += length
+		for scsc := scan; scan < len(new); scan++ {
+			length, pos = longestMatch(sa, old, new[scan:])
+			for ; scsc < scan+length; scsc++ {
+				if scsc+lastOffset < len(old) && old[scsc+lastOffset] == new[scsc] {
+					oldScore++
+				}
+			}
+			// 新的匹配明显优于沿用上一个偏移量时才切换
+			if length == oldScore && length != 0 || length > oldScore+8 {
+				break
+			}
+			if scan+lastOffset < len(old) && old[scan+lastOffset] == new[scan] {
+				oldScore--
+			}
+		}
+		if length == oldScore && scan != len(new) {
+			continue
+		}
+
+		// 从上一个匹配向前延伸，直到相同字节少于一半
+		s, sf, lenf := 0, 0, 0
+		for i := 0; lastScan+i < scan && lastPos+i < len(old); {
+			if old[lastPos+i] == new[lastScan+i] {
+				s++
+			}
+			i++
+			if s*2-i > sf*2-lenf {
+				sf, lenf = s, i
+			}
+		}
+		// 从当前匹配向后延伸
+		lenb := 0
+		if scan < len(new) {
+			s, sb := 0, 0
+			for i := 1; scan >= lastScan+i && pos >= i; i++ {
+				if old[pos-i] == new[scan-i] {
+					s++
+				}
+				if s*2-i > sb*2-lenb {
+					sb, lenb = s, i
+				}
+			}
+		}
+		// 两段重叠时选择使相同字节最多的分割点
+		if lastScan+lenf > scan-lenb {
+			overlap := lastScan + lenf - (scan - lenb)
+			s, ss, lens := 0, 0, 0
+			for i := 0; i < overlap; i++ {
+				if new[lastScan+lenf-overlap+i] == old[lastPos+lenf-overlap+i] {
+					s++
+				}
+				if new[scan-lenb+i] == old[pos-lenb+i] {
+					s--
+				}
+				if s > ss {
+					ss, lens = s, i+1
+				}
+			}
+			lenf += lens - overlap
+			lenb -= lens
+		}
+
+		emit(lastPos, lastScan, lenf, scan-lenb-(lastScan+lenf), pos-lenb-(lastPos+lenf))
+		lastScan, lastPos, lastOffset = scan-lenb, pos-lenb, pos-scan
+	}
+}
+
+// suffixArray 用倍增法构建 s 的后缀数组，包含空后缀（总是排在第一位）。
+func suffixArray(s []byte) []int {
+	n := len(s)
+	sa := make([]int, n+1)
+	rank := make([]int, n+1)
+	tmp := make([]int, n+1)
+	for i := range sa {
+		sa[i] = i
+		rank[i] = -1
+		if i < n {
+			rank[i] = int(s[i])
+		}
+	}
+	for k := 1; ; k <<= 1 {
+		second := func(i int) int {
+			if i+k <= n {
+				return rank[i+k]
+			}
+			return -1
+		}
+		compare := func(a, b int) int {
+			if c := cmp.Compare(rank[a], rank[b]); c != 0 {
+				return c
+			}
+			return cmp.Compare(second(a), second(b))
+		}
+		slices.SortFunc(sa, compare)
+		tmp[sa[0]] = 0
+		for i := 1; i <= n; i++ {
+			tmp[sa[i]] = tmp[sa[i-1]]
+			if compare(sa[i-1], sa[i]) < 0 {
+				tmp[sa[i]]++
+			}
+		}
+		copy(rank, tmp)
+		if rank[sa[n]] == n {
+			return sa
+		}
+	}
+}
+
+// longestMatch 在后缀数组上二分查找与 target 公共前缀最长的后缀，返回长度和位置。
+func longestMatch(sa []int, old, target []byte) (length, pos int) {
+	st, en := 0, len(sa)-1
+	for en-st >= 2 {
+		x := st + (en-st)/2
+		if bytes.Compare(old[sa[x]:], target) < 0 {
+			st = x
+		} else {
+			en = x
+		}
+	}
+	x, y := matchLen(old[sa[st]:], target), matchLen(old[sa[en]:], target)
+	if x > y {
+		return x, sa[st]
+	}
+	return y, sa[en]
+}
+
+func matchLen(a, b []byte) int {
+	n := min(len(a), len(b))
+	for i := 0; i < n; i++ {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+	return n
+}
@@ -0,0 +1,194 @@
+package byte
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestSuffixArray(t *testing.T) {
+	for _, s := range []string{"", "a", "banana", "mississippi", "aaaaaaa", "abcabcabc\x00\xff"} {
+		sa := suffixArray([]byte(s))
+		want := make([]int, len(s)+1)
+		for i := range want {
+			want[i] = i
+		}
+		sort.Slice(want, func(i, j int) bool { return s[want[i]:] < s[want[j]:] })
+		for i := range want {
+			if sa[i] != want[i] {
+				t.Errorf("suffixArray(%q) = %v, want %v", s, sa, want)
+				break
+			}
+		}
+	}
+}
+
+func mustApply(t *testing.T, old []byte, patch Patch) []byte {
+	t.Helper()
+	got, err := Apply(old, patch)
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	return got
+}
+
+func TestDiffApply(t *testing.T) {
+	tests := []struct{ old, new string }{
+		{"", ""},
+		{"", "brand new"},
+		{"all gone", ""},
+		{"same", "same"},
+		{"the quick brown fox", "the quick red fox jumps"},
+		{"key=1\nname=a\nport=80\n", "key=2\nname=a\nport=8080\nhost=x\n"},
+		{"abcabcabcabc", "abcXabcabcYabc"},
+		// 压缩比很高的补丁不能被长度上限误判为伪造
+		{"", strings.Repeat("\x00", 1<<20)},
+		{strings.Repeat("a", 1<<16), strings.Repeat("a", 1<<16)},
+	}
+	for _, test := range tests {
+		patch := Diff([]byte(test.old), []byte(test.new))
+		if got := mustApply(t, []byte(test.old), patch); string(got) != test.new {
+			t.Errorf("Apply(%q, Diff(%q, %q)) = %q", test.old, test.old, test.new, got)
+		}
+	}
+}
+
+func TestDiffCompact(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	old := make([]byte, 64<<10)
+	rng.Read(old)
+	// 在随机数据中做几处小修改，并插入、删除一些字节
+	new := bytes.Clone(old)
+	for i := 0; i < 20; i++ {
+		new[rng.Intn(len(new))]++
+	}
+	new = append(new[:1000:1000], append([]byte("inserted block"), new[1000:]...)...)
+	new = append(new[:30000:30000], new[30500:]...)
+
+	patch := Diff(old, new)
+	if len(patch) > 2048 {
+		t.Errorf("patch is %d bytes for a %d byte input with small edits", len(patch), len(new))
+	}
+	if got := mustApply(t, old, patch); !bytes.Equal(got, new) {
+		t.Error("Apply() result differs from new")
+	}
+}
+
+func TestApplyWrongBase(t *testing.T) {
+	patch := Diff([]byte("version 1 config"), []byte("version 2 config"))
+	for _, base := range []string{"version 1 confiG", "version 1 config!", ""} {
+		if _, err := Apply([]byte(base), patch); !errors.Is(err, ErrPatchBase) {
+			t.Errorf("Apply(%q) error = %v, want ErrPatchBase", base, err)
+		}
+	}
+}
+
+// craftPatch 按补丁格式构造任意内容的补丁，头部校验和正确，用于测试伪造的长度
+func craftPatch(old []byte, newLen uint64, records ...uint64) Patch {
+	var body bytes.Buffer
+	fw, _ := flate.NewWriter(&body, flate.BestCompression)
+	for _, v := range records {
+		fw.Write(binary.AppendUvarint(nil, v))
+	}
+	fw.Close()
+	patch := append([]byte(nil), patchMagic...)
+	patch = binary.AppendUvarint(patch, uint64(len(old)))
+	patch = binary.AppendUvarint(patch, newLen)
+	patch = binary.BigEndian.AppendUint32(patch, crc32.ChecksumIEEE(old))
+	patch = binary.BigEndian.AppendUint32(patch, 0)
+	patch = binary.BigEndian.AppendUint32(patch, crc32.ChecksumIEEE(patch))
+	return append(patch, body.Bytes()...)
+}
+
+func TestApplyCorrupt(t *testing.T) {
+	old := []byte("some old configuration data, some old configuration data")
+	new := []byte("some new configuration data, some old configuration data!!")
+	patch := Diff(old, new)
+
+	if _, err := Apply(old, patch[:len(patch)-1]); !errors.Is(err, ErrPatchCorrupt) {
+		t.Errorf("truncated patch error = %v, want ErrPatchCorrupt", err)
+	}
+	if _, err := Apply(old, append(bytes.Clone(patch), 0)); !errors.Is(err, ErrPatchCorrupt) {
+		t.Errorf("patch with trailing data error = %v, want ErrPatchCorrupt", err)
+	}
+	if _, err := Apply(old, Patch("not a patch")); !errors.Is(err, ErrPatchCorrupt) {
+		t.Errorf("garbage patch error = %v, want ErrPatchCorrupt", err)
+	}
+	// 声明很长的新增数据但没有实际数据：不能按声明的长度分配内存
+	for _, bad := range []Patch{
+		craftPatch(old, 1<<36, 0, 1<<36, 0),
+		craftPatch(old, 5000, 0, 5000, 0),
+		craftPatch(old, 5000, 5000, 0, 0),
+	} {
+		if _, err := Apply(old, bad); !errors.Is(err, ErrPatchCorrupt) {
+			t.Errorf("patch declaring %d-byte output error = %v, want ErrPatchCorrupt", len(bad), err)
+		}
+	}
+	// 头部的长度也在校验范围内
+	bad := bytes.Clone(patch)
+	bad[len(patchMagic)+1]++
+	if _, err := Apply(old, bad); !errors.Is(err, ErrPatchCorrupt) {
+		t.Errorf("patch with modified newLen error = %v, want ErrPatchCorrupt", err)
+	}
+
+	// 逐个修改补丁的每个字节，Apply 只能返回错误或正确的结果，不能 panic
+	for i := range patch {
+		bad := bytes.Clone(patch)
+		bad[i] ^= 0x5a
+		if got, err := Apply(old, bad); err == nil && !bytes.Equal(got, new) {
+			t.Errorf("corrupting byte %d produced wrong output without error", i)
+		}
+	}
+}
+
+func FuzzDiffApply(f *testing.F) {
+	f.Add([]byte(""), []byte(""))
+	f.Add([]byte("hello world"), []byte("hello brave new world"))
+	f.Add([]byte("aaaaaaaaaaaaaaaa"), []byte("aaaabaaaaaaacaaaa"))
+	f.Add([]byte("key=1\nport=80\n"), []byte("port=80\nkey=1\n"))
+	f.Add([]byte{0, 1, 2, 3, 255}, []byte{255, 3, 2, 1, 0})
+	f.Add([]byte("base"), []byte(craftPatch([]byte("base"), 1<<36, 0, 1<<36, 0)))
+
+	f.Fuzz(func(t *testing.T, old, new []byte) {
+		patch := Diff(old, new)
+		got, err := Apply(old, patch)
+		if err != nil {
+			t.Fatalf("Apply(Diff(%q, %q)) error = %v", old, new, err)
+		}
+		if !bytes.Equal(got, new) {
+			t.Fatalf("Apply(Diff(%q, %q)) = %q", old, new, got)
+		}
+		// 把 new 当作补丁应用时不能 panic
+		Apply(old, Patch(new))
+	})
+}
+
+func BenchmarkDiff(b *testing.B) {
+	rng := rand.New(rand.NewSource(1))
+	old := make([]byte, 64<<10)
+	rng.Read(old)
+	new := bytes.Clone(old)
+	for i := 0; i < 100; i++ {
+		new[rng.Intn(len(new))]++
+	}
+	patch := Diff(old, new)
+
+	b.Run("Diff", func(b *testing.B) {
+		b.SetBytes(int64(len(new)))
+		for i := 0; i < b.N; i++ {
+			Diff(old, new)
+		}
+	})
+	b.Run("Apply", func(b *testing.B) {
+		b.SetBytes(int64(len(new)))
+		for i := 0; i < b.N; i++ {
+			Apply(old, patch)
+		}
+	})
+}
@@ -0,0 +1,201 @@
+package byte
+
+import (
+	"bytes"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"go-libs-learning-kit/internal/wordbreak"
+)
+
+// ReplaceFlag 调整匹配规则，可以按位组合后通过 ReplaceMode.With 使用。
+// 设置任意标志后，匹配项都从 UTF-8 字符边界开始、在字符边界结束。
+type ReplaceFlag uint8
+
+const (
+	// IgnoreCase 按 Unicode 简单大小写折叠匹配，替换内容会沿用匹配文本的大小写形式，
+	// 例如 old 为 "go"、new 为 "rust" 时，"Go" 替换为 "Rust"，"GO" 替换为 "RUST"
+	IgnoreCase ReplaceFlag = 1 << iota
+	// WholeWord 只匹配两端都位于 Unicode 单词边界（UAX #29）上的内容
+	WholeWord
+	// RuneSafe 不匹配会拆开多字节字符的内容
+	RuneSafe
+)
+
+func (f ReplaceFlag) String() string {
+	var sb strings.Builder
+	for _, flag := range []struct {
+		f    ReplaceFlag
+		name string
+	}{{IgnoreCase, "IgnoreCase"}, {WholeWord, "WholeWord"}, {RuneSafe, "RuneSafe"}} {
+		if f&flag.f != 0 {
+			sb.WriteString("|" + flag.name)
+		}
+	}
+	return sb.String()
+}
+
+// matcher 按 flags 指定的规则查找 old。
+type matcher struct {
+	old    []byte
+	flags  ReplaceFlag
+	search Searcher // 没有设置 ReplaceFlag 时用于查找 old
+}
+
+func newMatcher(old []byte, flags ReplaceFlag) matcher {
+	m := matcher{old: old, flags: flags}
+	if flags == 0 {
+		m.search = newSearcher(old, SearchAuto)
+	}
+	return m
+}
+
+// find 从字符边界 from 开始逐个字符边界尝试匹配，只考虑起点小于 limit 的位置。
+// 找到时返回匹配项；找不到时返回第一个不小于 limit 的字符边界（不超过 len(s)），
+// 调用方可以从那里继续查找。
+func (m *matcher) find(s []byte, from, limit int) (sp span, next int, ok bool) {
+	i := from
+	for i < limit {
+		if end, ok := m.matchAt(s, i); ok {
+			return span{start: i, end: end}, i, true
+		}
+		if i == len(s) {
+			break
+		}
+		_, size := utf8.DecodeRune(s[i:])
+		i += size
+	}
+	return span{}, i, false
+}
+
+// matchAt 判断从字符边界 i 开始是否匹配，匹配时返回结束位置。
+func (m *matcher) matchAt(s []byte, i int) (int, bool) {
+	var end int
+	if m.flags&IgnoreCase != 0 {
+		n, ok := foldPrefix(s[i:], m.old)
+		if !ok {
+			return 0, false
+		}
+		end = i + n
+	} else {
+		if !bytes.HasPrefix(s[i:], m.old) || !runeEnd(s, i, i+len(m.old)) {
+			return 0, false
+		}
+		end = i + len(m.old)
+	}
+	if m.flags&WholeWord != 0 && !(wordbreak.IsBoundary(s, i) && wordbreak.IsBoundary(s, end)) {
+		return 0, false
+	}
+	return end, true
+}
+
+// runeEnd 判断从字符边界 i 开始逐个解码能否恰好停在 end。
+func runeEnd(s []byte, i, end int) bool {
+	for i < end {
+		_, size := utf8.DecodeRune(s[i:])
+		i += size
+	}
+	return i == end
+}
+
+// foldPrefix 判断 s 是否以忽略大小写后等于 pattern 的内容开头，返回这部分在 s 中的长度。
+// 无效的 UTF-8 字节只和相同的字节匹配。
+func foldPrefix(s, pattern []byte) (int, bool) {
+	n := 0
+	for len(pattern) > 0 {
+		if n == len(s) {
+			return 0, false
+		}
+		pr, psize := utf8.DecodeRune(pattern)
+		sr, ssize := utf8.DecodeRune(s[n:])
+		if pr == utf8.RuneError && psize == 1 || sr == utf8.RuneError && ssize == 1 {
+			if psize != ssize || pattern[0] != s[n] {
+				return 0, false
+			}
+		} else if !foldEqual(pr, sr) {
+			return 0, false
+		}
+		pattern = pattern[psize:]
+		n += ssize
+	}
+	return n, true
+}
+
+// foldEqual 判断两个字符是否在同一个简单大小写折叠轨道上，例如 k、K 与开尔文符号 K。
+func foldEqual(a, b rune) bool {
+	if a == b {
+		return true
+	}
+	for r := unicode.SimpleFold(a); r != a; r = unicode.SimpleFold(r) {
+		if r == b {
+			return true
+		}
+	}
+	return false
+}
+
+// appendReplacement 把 new 追加到 out；设置了 IgnoreCase 时按 matched 的大小写形式调整 new：
+// 全部大写则 new 转为大写，全部小写则转为小写，首字母大写其余小写则 new 也改为首字母大写，
+// 其他混合形式和没有字母的情况保持 new 不变。
+func appendReplacement(out, new, matched []byte, flags ReplaceFlag) []byte {
+	if flags&IgnoreCase == 0 {
+		return append(out, new...)
+	}
+	switch casePattern(matched) {
+	case caseUpper:
+		return append(out, bytes.ToUpper(new)...)
+	case caseLower:
+		return append(out, bytes.ToLower(new)...)
+	case caseTitle:
+		r, size := utf8.DecodeRune(new)
+		if r == utf8.RuneError && size <= 1 {
+			return append(out, new...)
+		}
+		out = utf8.AppendRune(out, unicode.ToTitle(r))
+		return append(out, bytes.ToLower(new[size:])...)
+	}
+	return append(out, new...)
+}
+
+type letterCase int
+
+const (
+	caseMixed letterCase = iota
+	caseUpper
+	caseLower
+	caseTitle
+)
+
+// casePattern 判断文本中字母的大小写形式，只有一个大写字母时视为首字母大写。
+func casePattern(b []byte) letterCase {
+	upper, lower, letters := 0, 0, 0
+	firstUpper := false
+	for _, r := range string(b) {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		isUpper := unicode.IsUpper(r) || unicode.IsTitle(r)
+		if letters == 0 {
+			firstUpper = isUpper
+		}
+		letters++
+		switch {
+		case isUpper:
+			upper++
+		case unicode.IsLower(r):
+			lower++
+		}
+	}
+	switch {
+	case letters == 0:
+		return caseMixed
+	case firstUpper && upper == 1 && lower == letters-1:
+		return caseTitle
+	case upper == letters:
+		return caseUpper
+	case lower == letters:
+		return caseLower
+	}
+	return caseMixed
+}
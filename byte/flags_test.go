@@ -0,0 +1,148 @@
+package byte
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestReplaceIgnoreCase(t *testing.T) {
+	tests := []struct {
+		s, old, new string
+		mode        ReplaceMode
+		want        string
+		count       int
+	}{
+		{"go Go GO gO", "go", "rust", ReplaceAll(), "rust Rust RUST rust", 4},
+		{"Go and GO", "GO", "Rust", ReplaceAll(), "Rust and RUST", 2},
+		{"Go and GO", "go", "Rust", ReplaceFirst(), "Rust and GO", 1},
+		{"Go and GO", "go", "Rust", ReplaceLast(), "Go and RUST", 1},
+		{"go Go GO", "go", "rust", ReplaceNth(2), "go Rust GO", 1},
+		// 混合大小写时 new 保持不变
+		{"gO", "go", "RuSt", ReplaceAll(), "RuSt", 1},
+		{"G", "g", "rust", ReplaceAll(), "Rust", 1},
+		// 非 ASCII 字母和长度不同的折叠：开尔文符号 K 占 3 个字节
+		{"ÉTÉ été Été", "été", "hiver", ReplaceAll(), "HIVER hiver Hiver", 3},
+		{"\u212aelvin", "kelvin", "x", ReplaceAll(), "X", 1},
+		{"ΣΊΣΥΦΟΣ", "σίσυφος", "sisyphus", ReplaceAll(), "SISYPHUS", 1},
+		{"a-b", "-", "+", ReplaceAll(), "a+b", 1},
+		{"no match", "xyz", "q", ReplaceAll(), "no match", 0},
+		{"a\xffB", "\xffb", "!", ReplaceAll(), "a!", 1},
+	}
+	for _, test := range tests {
+		got, n := Replace([]byte(test.s), []byte(test.old), []byte(test.new), test.mode.With(IgnoreCase))
+		if string(got) != test.want || n != test.count {
+			t.Errorf("Replace(%q, %q, %q, %v) = %q, %d; want %q, %d",
+				test.s, test.old, test.new, test.mode.With(IgnoreCase), got, n, test.want, test.count)
+		}
+	}
+}
+
+func TestReplaceWholeWord(t *testing.T) {
+	tests := []struct {
+		s, old, new string
+		want        string
+	}{
+		{"go gopher ago go", "go", "Go", "Go gopher ago Go"},
+		{"go_lang go-lang go.", "go", "Go", "go_lang Go-lang Go."},
+		// 单词中间的撇号和数字中间的小数点不是单词边界
+		{"can't can", "can", "may", "can't may"},
+		{"3.14 3 x3", "3", "three", "3.14 three x3"},
+		{"日本語の本", "本", "书", "日书語の书"},
+		{"カタカナ カ", "カ", "ka", "カタカナ ka"},
+		{"été e", "e", "E", "été E"},
+		{"line\r\nline", "line", "row", "row\r\nrow"},
+	}
+	for _, test := range tests {
+		got, _ := Replace([]byte(test.s), []byte(test.old), []byte(test.new), ReplaceAll().With(WholeWord))
+		if string(got) != test.want {
+			t.Errorf("Replace(%q, %q, %q, WholeWord) = %q, want %q", test.s, test.old, test.new, got, test.want)
+		}
+	}
+}
+
+func TestReplaceRuneSafe(t *testing.T) {
+	tests := []struct {
+		s, old, new string
+		want        string
+		count       int
+	}{
+		// "é" 是 C3 A9，"\xa9" 只会匹配单独的无效字节
+		{"é\xa9", "\xa9", "?", "é?", 1},
+		{"éé", "\xc3", "?", "éé", 0},
+		{"日本", "\xe6\x9c", "?", "日本", 0},
+		{"日本", "本", "ほん", "日ほん", 1},
+		{"ab", "", "-", "-a-b-", 3},
+	}
+	for _, test := range tests {
+		got, n := Replace([]byte(test.s), []byte(test.old), []byte(test.new), ReplaceAll().With(RuneSafe))
+		if string(got) != test.want || n != test.count {
+			t.Errorf("Replace(%q, %q, %q, RuneSafe) = %q, %d; want %q, %d",
+				test.s, test.old, test.new, got, n, test.want, test.count)
+		}
+		// 不加标志时会拆开多字节字符
+		if test.count == 0 {
+			if _, n := Replace([]byte(test.s), []byte(test.old), []byte(test.new), ReplaceAll()); n == 0 {
+				t.Errorf("Replace(%q, %q) without RuneSafe found no match", test.s, test.old)
+			}
+		}
+	}
+}
+
+func TestReplaceCombinedFlags(t *testing.T) {
+	got, n := Replace([]byte("Go gopher GO, go!"), []byte("go"), []byte("rust"), ReplaceAll().With(IgnoreCase|WholeWord))
+	if want := "Rust gopher RUST, rust!"; string(got) != want || n != 3 {
+		t.Errorf("Replace = %q, %d; want %q, 3", got, n, want)
+	}
+}
+
+func TestReplaceFlagsMatchPlain(t *testing.T) {
+	// 输入都是合法的 UTF-8 且 old 是完整的字符时，RuneSafe 与逐字节匹配结果相同
+	inputs := []string{"", "aaaa", "日本語日本", "go gopher go"}
+	olds := []string{"", "a", "aa", "日本", "go"}
+	modes := []ReplaceMode{ReplaceAll(), ReplaceFirst(), ReplaceLast(), ReplaceNth(2), ReplaceUpTo(2)}
+	for _, s := range inputs {
+		for _, old := range olds {
+			for _, mode := range modes {
+				want, wantN := Replace([]byte(s), []byte(old), []byte("<>"), mode)
+				got, n := Replace([]byte(s), []byte(old), []byte("<>"), mode.With(RuneSafe))
+				if !bytes.Equal(got, want) || n != wantN {
+					t.Errorf("Replace(%q, %q, %v) = %q, %d; want %q, %d", s, old, mode.With(RuneSafe), got, n, want, wantN)
+				}
+			}
+		}
+	}
+}
+
+func TestReplaceModeWith(t *testing.T) {
+	mode := ReplaceAll().With(IgnoreCase).With(WholeWord)
+	if got, want := mode.String(), "ReplaceAll|IgnoreCase|WholeWord"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	// With 不修改原来的模式
+	base := ReplaceFirst()
+	base.With(IgnoreCase)
+	if base.String() != "ReplaceFirst" {
+		t.Errorf("ReplaceFirst changed to %v", base)
+	}
+}
+
+func TestCasePattern(t *testing.T) {
+	tests := map[string]letterCase{
+		"":      caseMixed,
+		"123":   caseMixed,
+		"go":    caseLower,
+		"GO":    caseUpper,
+		"Go":    caseTitle,
+		"G":     caseTitle,
+		"gO":    caseMixed,
+		"GoGo":  caseMixed,
+		"Été":   caseTitle,
+		"GO-1":  caseUpper,
+		"ǅemal": caseTitle,
+	}
+	for s, want := range tests {
+		if got := casePattern([]byte(s)); got != want {
+			t.Errorf("casePattern(%q) = %v, want %v", s, got, want)
+		}
+	}
+}
@@ -0,0 +1,374 @@
+package byte
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrHexDump 表示 HexUndump 无法解析的十六进制转储文本
+var ErrHexDump = errors.New("byte: malformed hex dump")
+
+// maxSqueezed 限制 hexdump -C 中一个 "*" 能代表的字节数，防止错误的地址导致大量分配
+const maxSqueezed = 1 << 24
+
+// DumpFormat 是十六进制转储的格式
+type DumpFormat int
+
+const (
+	// DumpCanonical 与 hexdump -Cv 的输出相同：
+	//
+	//	00000000  48 65 6c 6c 6f 0a                                 |Hello.|
+	//	00000006
+	DumpCanonical DumpFormat = iota
+	// DumpXxd 与 xxd 的输出相同：
+	//
+	//	00000000: 4865 6c6c 6f0a                           Hello.
+	DumpXxd
+	// DumpGo 是 Go 的 []byte 字面量，可以直接粘贴到测试代码中
+	DumpGo
+	// DumpC 与 xxd -i 的输出相同，是 C 的数组定义和长度变量
+	DumpC
+)
+
+// DumpOptions 是 HexDump 的选项，零值表示 hexdump -C 格式、每行 16 字节
+type DumpOptions struct {
+	Format DumpFormat
+	// Offset 是第一个字节显示的地址，只用于 DumpCanonical 和 DumpXxd，不能是负数
+	Offset int
+	// Width 是每行的字节数，0 表示默认值（DumpC 与 xxd -i 一样为 12，其他格式为 16）
+	Width int
+	// Group 是每组的字节数，组之间多一个空格，只用于 DumpCanonical 和 DumpXxd。
+	// 0 表示默认值（DumpCanonical 为 8，DumpXxd 为 2），不小于 Width 时不分组
+	Group int
+	// Name 是 DumpC 中数组的名字，空表示 "data"
+	Name string
+}
+
+// HexDump 按 opts 指定的格式返回 data 的十六进制转储文本，HexUndump 可以把它还原。
+// opts.Offset 为负数时 panic。
+func HexDump(data []byte, opts DumpOptions) string {
+	if opts.Offset < 0 {
+		// 负的地址无法写成 HexUndump 能解析的形式
+		panic("byte: negative dump offset")
+	}
+	width := opts.Width
+	if width <= 0 {
+		width = 16
+		if opts.Format == DumpC {
+			width = 12
+		}
+	}
+	var sb strings.Builder
+	switch opts.Format {
+	case DumpCanonical, DumpXxd:
+		group := opts.Group
+		if group <= 0 {
+			group = 8
+			if opts.Format == DumpXxd {
+				group = 2
+			}
+		}
+		for i := 0; i < len(data); i += width {
+			dumpLine(&sb, opts.Format, opts.Offset+i, data[i:min(i+width, len(data))], width, group)
+		}
+		if opts.Format == DumpCanonical {
+			fmt.Fprintf(&sb, "%08x\n", opts.Offset+len(data))
+		}
+	case DumpGo:
+		if len(data) == 0 {
+			return "[]byte{}\n"
+		}
+		sb.WriteString("[]byte{\n")
+		for i := 0; i < len(data); i += width {
+			sb.WriteByte('\t')
+			for j, c := range data[i:min(i+width, len(data))] {
+				if j > 0 {
+					sb.WriteByte(' ')
+				}
+				fmt.Fprintf(&sb, "0x%02x,", c)
+			}
+			sb.WriteByte('\n')
+		}
+		sb.WriteString("}\n")
+	case DumpC:
+		name := opts.Name
+		if name == "" {
+			name = "data"
+		}
+		fmt.Fprintf(&sb, "unsigned char %s[] = {\n", name)
+		for i := 0; i < len(data); i += width {
+			sb.WriteString("  ")
+			end := min(i+width, len(data))
+			for j := i; j < end; j++ {
+				fmt.Fprintf(&sb, "0x%02x", data[j])
+				if j < len(data)-1 {
+					sb.WriteByte(',')
+				}
+				if j < end-1 {
+					sb.WriteByte(' ')
+				}
+			}
+			sb.WriteByte('\n')
+		}
+		fmt.Fprintf(&sb, "};\nunsigned int %s_len = %d;\n", name, len(data))
+	default:
+		panic("byte: unknown dump format")
+	}
+	return sb.String()
+}
+
+// dumpLine 写出 DumpCanonical 或 DumpXxd 格式的一行，不足 width 的部分用空格补齐
+func dumpLine(sb *strings.Builder, format DumpFormat, offset int, line []byte, width, group int) {
+	if format == DumpCanonical {
+		fmt.Fprintf(sb, "%08x  ", offset)
+	} else {
+		fmt.Fprintf(sb, "%08x: ", offset)
+	}
+	for i := 0; i < width; i++ {
+		if i > 0 && i%group == 0 {
+			sb.WriteByte(' ')
+		}
+		if i < len(line) {
+			fmt.Fprintf(sb, "%02x", line[i])
+		} else {
+			sb.WriteString("  ")
+		}
+		if format == DumpCanonical {
+			sb.WriteByte(' ')
+		}
+	}
+	if format == DumpCanonical {
+		sb.WriteString(" |")
+	} else {
+		sb.WriteString("  ")
+	}
+	for _, c := range line {
+		if c < 0x20 || c > 0x7e {
+			c = '.'
+		}
+		sb.WriteByte(c)
+	}
+	if format == DumpCanonical {
+		sb.WriteByte('|')
+	}
+	sb.WriteByte('\n')
+}
+
+// HexUndump 把 format 格式的十六进制转储文本还原为字节。
+// DumpCanonical 支持 hexdump -C 用 "*" 省略的重复行（最多 16MB）；
+// DumpGo 和 DumpC 的元素可以是任意进制的整数或字符字面量，注释会被忽略。
+// 文本格式错误时返回包装了 ErrHexDump 的错误。
+func HexUndump(dump string, format DumpFormat) ([]byte, error) {
+	switch format {
+	case DumpCanonical, DumpXxd:
+		return undumpLines(dump, format)
+	case DumpGo, DumpC:
+		return undumpLiteral(dump)
+	}
+	return nil, fmt.Errorf("%w: unknown format %d", ErrHexDump, format)
+}
+
+// undumpLines 解析 DumpCanonical 和 DumpXxd 格式，要求每行的地址与已解析的字节数连续
+func undumpLines(dump string, format DumpFormat) ([]byte, error) {
+	var out, prev []byte
+	base, started, squeezed, ended := 0, false, false, false
+	for n, line := range strings.Split(dump, "\n") {
+		line = strings.TrimRight(line, " \r")
+		if line == "" {
+			continue
+		}
+		if ended {
+			return nil, fmt.Errorf("%w: line %d: data after end offset", ErrHexDump, n+1)
+		}
+		if format == DumpCanonical && line == "*" {
+			if prev == nil || squeezed {
+				return nil, fmt.Errorf("%w: line %d: unexpected \"*\"", ErrHexDump, n+1)
+			}
+			squeezed = true
+			continue
+		}
+
+		i := 0
+		for i < len(line) && isHexDigit(line[i]) {
+			i++
+		}
+		offset, err := strconv.ParseUint(line[:i], 16, 63)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: bad offset", ErrHexDump, n+1)
+		}
+		rest := line[i:]
+		var hex string
+		if format == DumpCanonical {
+			// 只有地址的行是结束标记
+			ended = rest == ""
+			if !ended && !strings.HasPrefix(rest, "  ") {
+				return nil, fmt.Errorf("%w: line %d: missing separator after offset", ErrHexDump, n+1)
+			}
+			hex, _, _ = strings.Cut(rest, "|")
+		} else {
+			var ok bool
+			if rest, ok = strings.CutPrefix(rest, ": "); !ok {
+				return nil, fmt.Errorf("%w: line %d: missing separator after offset", ErrHexDump, n+1)
+			}
+			// 十六进制部分与 ASCII 部分之间至少有两个空格
+			hex, _, _ = strings.Cut(rest, "  ")
+		}
+
+		if !started {
+			base, started = int(offset), true
+		}
+		if squeezed {
+			// 用上一行的内容补齐省略的部分
+			gap := int(offset) - base - len(out)
+			if gap <= 0 || gap%len(prev) != 0 || gap > maxSqueezed {
+				return nil, fmt.Errorf("%w: line %d: offset does not match \"*\"", ErrHexDump, n+1)
+			}
+			for ; gap > 0; gap -= len(prev) {
+				out = append(out, prev...)
+			}
+			squeezed = false
+		}
+		if int(offset) != base+len(out) {
+			return nil, fmt.Errorf("%w: line %d: offset %#x, want %#x", ErrHexDump, n+1, offset, base+len(out))
+		}
+		start := len(out)
+		if out, err = appendHexFields(out, hex); err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrHexDump, n+1, err)
+		}
+		if !ended && len(out) == start {
+			return nil, fmt.Errorf("%w: line %d: no data", ErrHexDump, n+1)
+		}
+		prev = out[start:]
+	}
+	if squeezed {
+		return nil, fmt.Errorf("%w: missing offset after \"*\"", ErrHexDump)
+	}
+	if out == nil {
+		out = []byte{}
+	}
+	return out, nil
+}
+
+// appendHexFields 解析以空格分隔的十六进制数字组，每组的位数必须是偶数
+func appendHexFields(out []byte, s string) ([]byte, error) {
+	for _, field := range strings.Fields(s) {
+		if len(field)%2 != 0 {
+			return out, fmt.Errorf("odd length hex group %q", field)
+		}
+		for i := 0; i < len(field); i += 2 {
+			v, err := strconv.ParseUint(field[i:i+2], 16, 8)
+			if err != nil {
+				return out, fmt.Errorf("bad hex group %q", field)
+			}
+			out = append(out, byte(v))
+		}
+	}
+	return out, nil
+}
+
+// undumpLiteral 解析 DumpGo 和 DumpC 格式：取第一对花括号中以逗号分隔的元素
+func undumpLiteral(dump string) ([]byte, error) {
+	elems, ok := literalElements(dump)
+	if !ok {
+		return nil, fmt.Errorf("%w: missing braces", ErrHexDump)
+	}
+	out := []byte{}
+	if len(elems) == 1 && strings.TrimSpace(elems[0]) == "" {
+		return out, nil
+	}
+	for i, elem := range elems {
+		elem = strings.TrimSpace(elem)
+		if elem == "" {
+			// 只允许末尾的逗号
+			if i == len(elems)-1 && i > 0 {
+				continue
+			}
+			return nil, fmt.Errorf("%w: empty element %d", ErrHexDump, i)
+		}
+		v, err := parseByteLiteral(elem)
+		if err != nil {
+			return nil, fmt.Errorf("%w: element %d: %q", ErrHexDump, i, elem)
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+// literalElements 按逗号切分第一对花括号之间的内容，注释替换为空格。
+// 字符字面量（如 ',' 和 '}'）整体作为元素的一部分，其中的逗号、花括号和注释符号不起作用。
+func literalElements(dump string) ([]string, bool) {
+	var elems []string
+	var elem strings.Builder
+	inBraces := false
+	for i := 0; i < len(dump); {
+		c := dump[i]
+		switch {
+		case strings.HasPrefix(dump[i:], "//"):
+			n := strings.IndexByte(dump[i:], '\n')
+			if n < 0 {
+				n = len(dump) - i
+			}
+			i += n
+			elem.WriteByte(' ')
+			continue
+		case strings.HasPrefix(dump[i:], "/*"):
+			n := strings.Index(dump[i+2:], "*/")
+			if n < 0 {
+				n = len(dump) - i - 4
+			}
+			i += n + 4
+			elem.WriteByte(' ')
+			continue
+		case c == '\'':
+			n := charLiteralLen(dump[i:])
+			if inBraces {
+				elem.WriteString(dump[i : i+n])
+			}
+			i += n
+			continue
+		case !inBraces:
+			inBraces = c == '{'
+		case c == ',':
+			elems = append(elems, elem.String())
+			elem.Reset()
+		case c == '}':
+			return append(elems, elem.String()), true
+		default:
+			elem.WriteByte(c)
+		}
+		i++
+	}
+	return nil, false
+}
+
+// charLiteralLen 返回 s 开头的字符字面量（包括两端的单引号）的长度，没有结束的单引号时返回 len(s)
+func charLiteralLen(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '\'':
+			return i + 1
+		}
+	}
+	return len(s)
+}
+
+// parseByteLiteral 解析一个不超过 255 的整数或字符字面量
+func parseByteLiteral(s string) (byte, error) {
+	if len(s) >= 3 && s[0] == '\'' && s[len(s)-1] == '\'' {
+		r, _, tail, err := strconv.UnquoteChar(s[1:len(s)-1], '\'')
+		if err != nil || tail != "" || r > 0xff {
+			return 0, strconv.ErrSyntax
+		}
+		return byte(r), nil
+	}
+	v, err := strconv.ParseUint(s, 0, 8)
+	return byte(v), err
+}
+
+func isHexDigit(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
@@ -0,0 +1,168 @@
+package byte
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+var hexSample = []byte("Hello, world!\n\x00\x01\x02")
+
+func TestHexDumpFormats(t *testing.T) {
+	tests := []struct {
+		opts DumpOptions
+		want string
+	}{
+		{DumpOptions{}, "" +
+			"00000000  48 65 6c 6c 6f 2c 20 77  6f 72 6c 64 21 0a 00 01  |Hello, world!...|\n" +
+			"00000010  02                                                |.|\n" +
+			"00000011\n"},
+		{DumpOptions{Format: DumpXxd}, "" +
+			"00000000: 4865 6c6c 6f2c 2077 6f72 6c64 210a 0001  Hello, world!...\n" +
+			"00000010: 02                                       .\n"},
+		{DumpOptions{Format: DumpGo, Width: 8}, "" +
+			"[]byte{\n" +
+			"\t0x48, 0x65, 0x6c, 0x6c, 0x6f, 0x2c, 0x20, 0x77,\n" +
+			"\t0x6f, 0x72, 0x6c, 0x64, 0x21, 0x0a, 0x00, 0x01,\n" +
+			"\t0x02,\n" +
+			"}\n"},
+		{DumpOptions{Format: DumpC, Name: "resp"}, "" +
+			"unsigned char resp[] = {\n" +
+			"  0x48, 0x65, 0x6c, 0x6c, 0x6f, 0x2c, 0x20, 0x77, 0x6f, 0x72, 0x6c, 0x64,\n" +
+			"  0x21, 0x0a, 0x00, 0x01, 0x02\n" +
+			"};\n" +
+			"unsigned int resp_len = 17;\n"},
+		{DumpOptions{Offset: 0x1000, Width: 6, Group: 3}, "" +
+			"00001000  48 65 6c  6c 6f 2c  |Hello,|\n" +
+			"00001006  20 77 6f  72 6c 64  | world|\n" +
+			"0000100c  21 0a 00  01 02     |!....|\n" +
+			"00001011\n"},
+		{DumpOptions{Format: DumpXxd, Width: 8, Group: 8}, "" +
+			"00000000: 48656c6c6f2c2077  Hello, w\n" +
+			"00000008: 6f726c64210a0001  orld!...\n" +
+			"00000010: 02                .\n"},
+	}
+	for _, test := range tests {
+		got := HexDump(hexSample, test.opts)
+		if got != test.want {
+			t.Errorf("HexDump(%+v) =\n%s\nwant\n%s", test.opts, got, test.want)
+		}
+		if back, err := HexUndump(got, test.opts.Format); err != nil || !bytes.Equal(back, hexSample) {
+			t.Errorf("HexUndump(HexDump(%+v)) = %q, %v", test.opts, back, err)
+		}
+	}
+}
+
+func TestHexDumpEmpty(t *testing.T) {
+	for f := DumpCanonical; f <= DumpC; f++ {
+		got, err := HexUndump(HexDump(nil, DumpOptions{Format: f}), f)
+		if err != nil || got == nil || len(got) != 0 {
+			t.Errorf("format %d: HexUndump(HexDump(nil)) = %#v, %v", f, got, err)
+		}
+	}
+}
+
+func TestHexUndumpSqueezed(t *testing.T) {
+	// hexdump -C 用 "*" 省略与上一行相同的行
+	dump := "" +
+		"00000000  00 00 00 00 00 00 00 00  00 00 00 00 00 00 00 00  |................|\n" +
+		"*\n" +
+		"00000030  ff fe                                             |..|\n" +
+		"00000032\n"
+	want := append(make([]byte, 48), 0xff, 0xfe)
+	if got, err := HexUndump(dump, DumpCanonical); err != nil || !bytes.Equal(got, want) {
+		t.Errorf("HexUndump = %x, %v; want %x", got, err, want)
+	}
+}
+
+func TestHexUndumpLiteral(t *testing.T) {
+	goSrc := `body := []byte{
+		0x89, 'P', 'N', 'G', // 文件头
+		13, 10, 0o32, 0b1010, /* 换行 */
+		'\n', '\xff',
+	}`
+	want := []byte{0x89, 'P', 'N', 'G', 13, 10, 0o32, 10, '\n', 0xff}
+	if got, err := HexUndump(goSrc, DumpGo); err != nil || !bytes.Equal(got, want) {
+		t.Errorf("HexUndump(Go) = %x, %v; want %x", got, err, want)
+	}
+
+	cSrc := "static const unsigned char png[] = { 0x89, 0120, 78, 0X47 };\nunsigned int png_len = 4;\n"
+	if got, err := HexUndump(cSrc, DumpC); err != nil || !bytes.Equal(got, want[:4]) {
+		t.Errorf("HexUndump(C) = %x, %v; want %x", got, err, want[:4])
+	}
+
+	// 字符字面量中的逗号、花括号、引号和注释符号不是分隔符
+	for _, test := range []struct {
+		src  string
+		want []byte
+	}{
+		{"[]byte{','}", []byte(",")},
+		{"[]byte{'a', ',', 'b',}", []byte("a,b")},
+		{"[]byte{'}', '{', /* '}' */ '/', '\\'', '\\\\'}", []byte("}{/'\\")},
+		{"[]byte{'/' /* c */, '*'}", []byte("/*")},
+	} {
+		if got, err := HexUndump(test.src, DumpGo); err != nil || !bytes.Equal(got, test.want) {
+			t.Errorf("HexUndump(%q) = %q, %v; want %q", test.src, got, err, test.want)
+		}
+	}
+}
+
+func TestHexUndumpErrors(t *testing.T) {
+	tests := []struct {
+		format DumpFormat
+		dump   string
+	}{
+		{DumpCanonical, "00000000  4x                                                |.|\n"},
+		{DumpCanonical, "00000000  48 65                                             |He|\n00000003  6c |l|\n"},
+		{DumpCanonical, "*\n00000010\n"},
+		{DumpCanonical, "00000000  00                                                |.|\n*\n"},
+		{DumpCanonical, "00000000  00                                                |.|\n*\n7fffffff\n"},
+		{DumpCanonical, "00000001\n00000001  00  |.|\n"},
+		{DumpCanonical, "zz  00\n"},
+		{DumpXxd, "00000000 4865  He\n"},
+		{DumpXxd, "00000000: 486  H\n"},
+		{DumpXxd, "00000000: 4865  He\n00000004: 6c6c  ll\n"},
+		{DumpGo, "[]byte{0x100}"},
+		{DumpGo, "[]byte{0x01,, 0x02}"},
+		{DumpGo, "[]byte{'ab'}"},
+		{DumpGo, "[]byte{'日'}"},
+		{DumpGo, "[]byte{'}"},
+		{DumpGo, "[]byte{',}"},
+		{DumpC, "unsigned char x[] = 0x01;"},
+		{DumpFormat(99), ""},
+	}
+	for _, test := range tests {
+		if got, err := HexUndump(test.dump, test.format); !errors.Is(err, ErrHexDump) {
+			t.Errorf("HexUndump(%q, %d) = %x, %v; want ErrHexDump", test.dump, test.format, got, err)
+		}
+	}
+}
+func TestHexDumpNegativeOffset(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("HexDump with negative Offset did not panic")
+		}
+	}()
+	HexDump(hexSample, DumpOptions{Offset: -16})
+}
+
+func FuzzHexDump(f *testing.F) {
+	f.Add(hexSample, 0, 0, 0)
+	f.Add([]byte{}, 5, 0, 2)
+	f.Add(make([]byte, 100), 0x7ff0, 7, 3)
+	f.Add([]byte("  |pipes|  and  spaces  "), 1, 4, 4)
+
+	f.Fuzz(func(t *testing.T, data []byte, offset, width, group int) {
+		offset, width, group = offset&0xffffff, width%64, group%64
+		for format := DumpCanonical; format <= DumpC; format++ {
+			opts := DumpOptions{Format: format, Offset: offset, Width: width, Group: group}
+			dump := HexDump(data, opts)
+			got, err := HexUndump(dump, format)
+			if err != nil || !bytes.Equal(got, data) {
+				t.Fatalf("HexUndump(HexDump(%q, %+v)) = %q, %v\n%s", data, opts, got, err, dump)
+			}
+			// 任意文本都不能让解析 panic
+			HexUndump(string(data), format)
+		}
+	})
+}
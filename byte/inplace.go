@@ -0,0 +1,82 @@
+package byte
+
+// ReplaceInPlace 与 Replace 的规则相同，但直接改写 buf 并返回缩短后的 buf[:n] 以及替换的次数。
+// 替换后的内容总是不比原来长，所以从左到右写入时不会覆盖还没有读到的数据。
+//
+// 只有 len(new) <= len(old) 且没有设置 IgnoreCase、WholeWord 时才能原地替换，此时不分配内存。
+// 其他情况退回到 Replace：IgnoreCase 会改变匹配和替换内容的长度，
+// WholeWord 需要查看已经被改写的匹配项之前的原始内容。
+// 退回后结果能放进 buf 时仍然写回 buf，否则返回新分配的切片。
+//
+// 返回的切片之后 buf 中剩余的内容未定义。new 不能与 buf 共享内存。
+func ReplaceInPlace(buf, old, new []byte, mode ReplaceMode) ([]byte, int) {
+	if len(new) > len(old) || mode.flags&^RuneSafe != 0 {
+		out, n := Replace(buf, old, new, mode)
+		if len(out) <= len(buf) {
+			return buf[:copy(buf, out)], n
+		}
+		return out, n
+	}
+
+	m := newMatcher(old, mode.flags)
+	limit := -1
+	switch mode.kind {
+	case replaceNone:
+		return buf, 0
+	case replaceFirst:
+		limit = 1
+	case replaceUpTo:
+		limit = mode.n
+	case replaceLast:
+		if sp, ok := m.findLast(buf); ok {
+			return splice(buf, sp, new), 1
+		}
+		return buf, 0
+	case replaceNth:
+		if sp, ok := m.nth(buf, mode.n); ok {
+			return splice(buf, sp, new), 1
+		}
+		return buf, 0
+	}
+
+	// w 是写入位置，r 是下一段要保留的原始内容的起点，w <= r 始终成立
+	w, r, from, count := 0, 0, 0, 0
+	for limit < 0 || count < limit {
+		sp, ok := m.next(buf, from)
+		if !ok {
+			break
+		}
+		w += copy(buf[w:], buf[r:sp.start])
+		w += copy(buf[w:], new)
+		r = sp.end
+		count++
+		if from, ok = resume(buf, sp); !ok {
+			break
+		}
+	}
+	w += copy(buf[w:], buf[r:])
+	return buf[:w], count
+}
+
+// nth 返回从左到右第 n 个不重叠的匹配项，不分配内存。
+func (m *matcher) nth(s []byte, n int) (span, bool) {
+	if n < 1 {
+		return span{}, false
+	}
+	for from, k := 0, 1; ; k++ {
+		sp, ok := m.next(s, from)
+		if !ok || k == n {
+			return sp, ok
+		}
+		if from, ok = resume(s, sp); !ok {
+			return span{}, false
+		}
+	}
+}
+
+// splice 把 buf 中 sp 范围的内容原地替换为不比它长的 new。
+func splice(buf []byte, sp span, new []byte) []byte {
+	w := sp.start + copy(buf[sp.start:], new)
+	w += copy(buf[w:], buf[sp.end:])
+	return buf[:w]
+}
@@ -0,0 +1,92 @@
+package byte
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+var inPlaceModes = []ReplaceMode{
+	ReplaceAll(), ReplaceFirst(), ReplaceNone(), ReplaceLast(), ReplaceNth(2), ReplaceNth(9),
+	ReplaceUpTo(2), ReplaceUpTo(-1), ReplaceAll().With(RuneSafe), ReplaceLast().With(RuneSafe),
+	ReplaceAll().With(IgnoreCase), ReplaceAll().With(WholeWord),
+}
+
+func TestReplaceInPlace(t *testing.T) {
+	cases := append(streamCases, []struct{ s, old, new string }{
+		{"password=hunter2 password=hunter2", "hunter2", "***"},
+		{"a,b,,c", ",", ""},
+		{"ab", "", ""},
+		{"gogo", "go", "GO"},
+	}...)
+	for _, c := range cases {
+		for _, mode := range inPlaceModes {
+			want, wantN := Replace([]byte(c.s), []byte(c.old), []byte(c.new), mode)
+			buf := []byte(c.s)
+			got, n := ReplaceInPlace(buf, []byte(c.old), []byte(c.new), mode)
+			if !bytes.Equal(got, want) || n != wantN {
+				t.Errorf("ReplaceInPlace(%q, %q, %q, %v) = %q, %d; want %q, %d",
+					c.s, c.old, c.new, mode, got, n, want, wantN)
+			}
+			// 结果放得下时总是写回 buf
+			if len(got) <= len(buf) && len(got) > 0 && &got[0] != &buf[0] {
+				t.Errorf("ReplaceInPlace(%q, %q, %q, %v) did not reuse buf", c.s, c.old, c.new, mode)
+			}
+		}
+	}
+}
+
+func TestReplaceInPlaceZeroAllocs(t *testing.T) {
+	src := []byte(strings.Repeat("user=bob password=hunter2 日本 ", 20))
+	buf := make([]byte, len(src))
+	modes := []ReplaceMode{
+		ReplaceAll(), ReplaceFirst(), ReplaceLast(), ReplaceNth(3), ReplaceUpTo(5),
+		ReplaceAll().With(RuneSafe), ReplaceLast().With(RuneSafe),
+	}
+	// 64 字节以上的模式使用 Horspool，跳跃表也不能单独分配
+	long := []byte(strings.Repeat("password=hunter2 ", 4))
+	longSrc := []byte(strings.Repeat("user=bob "+string(long)+"日本 ", 20))
+	longBuf := make([]byte, len(longSrc))
+	for _, mode := range modes {
+		allocs := testing.AllocsPerRun(100, func() {
+			copy(buf, src)
+			ReplaceInPlace(buf, []byte("hunter2"), []byte("***"), mode)
+		})
+		if allocs != 0 {
+			t.Errorf("ReplaceInPlace(%v) allocs = %v, want 0", mode, allocs)
+		}
+		allocs = testing.AllocsPerRun(100, func() {
+			copy(longBuf, longSrc)
+			ReplaceInPlace(longBuf, long, []byte("***"), mode)
+		})
+		if allocs != 0 {
+			t.Errorf("ReplaceInPlace(%v) with %d-byte pattern allocs = %v, want 0", mode, len(long), allocs)
+		}
+	}
+}
+
+func BenchmarkReplaceInPlace(b *testing.B) {
+	// 日志脱敏：把令牌替换为等长或更短的占位符
+	line := "2024-05-01T12:00:00Z INFO request id=42 user=bob token=sk_live_abcdef0123456789 status=200\n"
+	src := []byte(strings.Repeat(line, 1000))
+	old := []byte("sk_live_abcdef0123456789")
+	new := []byte("[REDACTED]")
+
+	b.Run("ReplaceWithMode", func(b *testing.B) {
+		b.SetBytes(int64(len(src)))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			replaceWithMode(src, old, new, ReplaceAll())
+		}
+	})
+
+	b.Run("InPlace", func(b *testing.B) {
+		buf := make([]byte, len(src))
+		b.SetBytes(int64(len(src)))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			copy(buf, src)
+			ReplaceInPlace(buf, old, new, ReplaceAll())
+		}
+	})
+}
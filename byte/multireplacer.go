@@ -0,0 +1,195 @@
+package byte
+
+import (
+	"bytes"
+	"sort"
+)
+
+// MultiReplacer 用 Aho-Corasick 自动机一次扫描同时替换多个模式，结果不依赖替换的先后顺序。
+// 扫描次数与模式数量无关，但模式很多时状态转移表超出 CPU 缓存，速度会明显下降：
+// 在 BenchmarkMultiReplacer 中，几十到上千个模式时比 strings.Replacer 快，上万个模式时两者相当。
+//
+// 匹配采用最左最长规则：从左到右找到最早开始的匹配项，同一位置有多个模式匹配时取最长的，
+// 替换后从匹配项之后继续查找，匹配项之间不重叠。
+// MultiReplacer 创建后只读，可以被多个 goroutine 同时使用。
+type MultiReplacer struct {
+	olds, news [][]byte
+
+	// classes 把字节映射为等价类，没有出现在任何模式中的字节都归为 0 类，
+	// 以此压缩状态转移表的宽度
+	classes [256]byte
+	// first 标记能作为某个模式首字节的字节，在根状态下用来快速跳过
+	first  [256]bool
+	stride int
+	// next 是完整的状态转移表（goto 与 fail 合并后的 DFA），下标为 state*stride+class。
+	// 值为目标状态左移一位，最低位表示目标状态有模式结尾，查找时大多数状态不需要再读取 states
+	next   []int32
+	states []acState
+}
+
+// acState 保存查找时需要的状态信息，放在一起以减少缓存未命中
+type acState struct {
+	depth int32 // 状态对应的前缀长度
+	out   int32 // 以该状态结尾的最长模式的下标，没有时为 -1
+	size  int32 // out 对应模式的长度
+}
+
+// NewMultiReplacer 根据 old→new 的映射创建 MultiReplacer，空的 old 会被忽略。
+func NewMultiReplacer(pairs map[string]string) *MultiReplacer {
+	m := &MultiReplacer{}
+	keys := make([]string, 0, len(pairs))
+	for old := range pairs {
+		if old != "" {
+			keys = append(keys, old)
+		}
+	}
+	sort.Strings(keys)
+	for _, old := range keys {
+		m.olds = append(m.olds, []byte(old))
+		m.news = append(m.news, []byte(pairs[old]))
+	}
+
+	// 为出现过的字节分配等价类
+	m.stride = 1
+	for _, old := range m.olds {
+		m.first[old[0]] = true
+		for _, c := range old {
+			if m.classes[c] == 0 {
+				m.classes[c] = byte(m.stride)
+				m.stride++
+			}
+		}
+	}
+
+	m.build()
+	return m
+}
+
+// build 先构建 trie，再按广度优先顺序计算失败链接并补全转移表。
+func (m *MultiReplacer) build() {
+	m.newState(0)
+	for p, old := range m.olds {
+		s := int32(0)
+		for _, c := range old {
+			i := int(s)*m.stride + int(m.classes[c])
+			if m.next[i] == 0 {
+				m.next[i] = m.newState(m.states[s].depth + 1)
+			}
+			s = m.next[i]
+		}
+		m.states[s].out, m.states[s].size = int32(p), int32(len(old))
+	}
+
+	fail := make([]int32, len(m.states))
+	queue := []int32{0}
+	for c := 0; c < m.stride; c++ {
+		if t := m.next[c]; t != 0 {
+			queue = append(queue, t)
+		}
+	}
+	for k := 1; k < len(queue); k++ {
+		s := queue[k]
+		// 自身不是模式结尾时，继承失败链接上最长的模式
+		if f := m.states[fail[s]]; m.states[s].out < 0 {
+			m.states[s].out, m.states[s].size = f.out, f.size
+		}
+		for c := 0; c < m.stride; c++ {
+			i := int(s)*m.stride + c
+			f := m.next[int(fail[s])*m.stride+c]
+			if t := m.next[i]; t != 0 {
+				fail[t] = f
+				queue = append(queue, t)
+			} else {
+				m.next[i] = f
+			}
+		}
+	}
+
+	// 按广度优先顺序重新编号，查找时最常访问的浅层状态在内存中相邻
+	id := make([]int32, len(queue))
+	for k, s := range queue {
+		id[s] = int32(k)
+	}
+	next := make([]int32, len(m.next))
+	states := make([]acState, len(m.states))
+	for k, s := range queue {
+		states[k] = m.states[s]
+		for c := 0; c < m.stride; c++ {
+			t := m.next[int(s)*m.stride+c]
+			v := id[t] << 1
+			if m.states[t].out >= 0 {
+				v |= 1
+			}
+			next[k*m.stride+c] = v
+		}
+	}
+	m.next, m.states = next, states
+}
+
+func (m *MultiReplacer) newState(depth int32) int32 {
+	s := int32(len(m.states))
+	m.states = append(m.states, acState{depth: depth, out: -1})
+	m.next = append(m.next, make([]int32, m.stride)...)
+	return s
+}
+
+// Replace 返回 s 替换后的副本以及替换的次数。
+func (m *MultiReplacer) Replace(s []byte) ([]byte, int) {
+	var out []byte
+	count, last := 0, 0
+	for last < len(s) {
+		start, p := m.find(s, last)
+		if p < 0 {
+			break
+		}
+		if out == nil {
+			out = make([]byte, 0, len(s))
+		}
+		out = append(out, s[last:start]...)
+		out = append(out, m.news[p]...)
+		last = start + len(m.olds[p])
+		count++
+	}
+	if out == nil {
+		return bytes.Clone(s), 0
+	}
+	return append(out, s[last:]...), count
+}
+
+// ReplaceString 与 Replace 相同，处理字符串。
+func (m *MultiReplacer) ReplaceString(s string) string {
+	out, n := m.Replace([]byte(s))
+	if n == 0 {
+		return s
+	}
+	return string(out)
+}
+
+// find 从 from 开始查找最左最长的匹配项，返回起始位置和模式下标，找不到时下标为 -1。
+func (m *MultiReplacer) find(s []byte, from int) (start, pattern int) {
+	start, pattern = -1, -1
+	state := int32(0)
+	for j := from; j < len(s); j++ {
+		if state == 0 {
+			for j < len(s) && !m.first[s[j]] {
+				j++
+			}
+			if j == len(s) {
+				break
+			}
+		}
+		t := m.next[int(state)*m.stride+int(m.classes[s[j]])]
+		state = t >> 1
+		// 当前状态对应的前缀从 start 之后才开始，不可能再有更靠左或更长的匹配
+		if pattern >= 0 && j+1-int(m.states[state].depth) > start {
+			break
+		}
+		if t&1 != 0 {
+			st := &m.states[state]
+			if i := j + 1 - int(st.size); pattern < 0 || i <= start {
+				start, pattern = i, int(st.out)
+			}
+		}
+	}
+	return start, pattern
+}
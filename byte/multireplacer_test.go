@@ -0,0 +1,169 @@
+package byte
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// multiReplaceReference 是最左最长规则的朴素实现：在每个位置逐个尝试所有模式
+func multiReplaceReference(s string, pairs map[string]string) (string, int) {
+	var sb strings.Builder
+	count := 0
+	for i := 0; i < len(s); {
+		best := ""
+		for old := range pairs {
+			if old != "" && len(old) > len(best) && strings.HasPrefix(s[i:], old) {
+				best = old
+			}
+		}
+		if best == "" {
+			sb.WriteByte(s[i])
+			i++
+			continue
+		}
+		sb.WriteString(pairs[best])
+		i += len(best)
+		count++
+	}
+	return sb.String(), count
+}
+
+func TestMultiReplacer(t *testing.T) {
+	tests := []struct {
+		pairs map[string]string
+		s     string
+		want  string
+		count int
+	}{
+		{map[string]string{"go": "Go", "gopher": "Gopher"}, "go gopher go", "Go Gopher Go", 3},
+		{map[string]string{"a": "1", "ab": "2", "abc": "3"}, "abcabxa", "32x1", 3},
+		// 最左优先于最长
+		{map[string]string{"bcd": "X", "ab": "Y"}, "abcd", "Ycd", 1},
+		{map[string]string{"he": "1", "she": "2", "his": "3", "hers": "4"}, "ushers", "u2rs", 1},
+		{map[string]string{"he": "1", "his": "3", "hers": "4"}, "ushers", "us4", 1},
+		// 替换结果不会再被匹配，和替换顺序无关
+		{map[string]string{"a": "b", "b": "a"}, "abba", "baab", 4},
+		{map[string]string{"日本": "にほん", "本語": "ほんご"}, "日本語", "にほん語", 1},
+		{map[string]string{"": "x", "q": "Q"}, "aqa", "aQa", 1},
+		{map[string]string{"x": "y"}, "", "", 0},
+		{map[string]string{}, "unchanged", "unchanged", 0},
+	}
+	for _, test := range tests {
+		m := NewMultiReplacer(test.pairs)
+		got, n := m.Replace([]byte(test.s))
+		if string(got) != test.want || n != test.count {
+			t.Errorf("Replace(%q) with %v = %q, %d; want %q, %d", test.s, test.pairs, got, n, test.want, test.count)
+		}
+		if got := m.ReplaceString(test.s); got != test.want {
+			t.Errorf("ReplaceString(%q) with %v = %q, want %q", test.s, test.pairs, got, test.want)
+		}
+	}
+}
+
+func TestMultiReplacerRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	randString := func(n int) string {
+		b := make([]byte, n)
+		for i := range b {
+			b[i] = "abc"[rng.Intn(3)]
+		}
+		return string(b)
+	}
+	for iter := 0; iter < 500; iter++ {
+		pairs := make(map[string]string)
+		for k := rng.Intn(6); k >= 0; k-- {
+			pairs[randString(1+rng.Intn(4))] = fmt.Sprint(k)
+		}
+		s := randString(rng.Intn(40))
+		want, wantN := multiReplaceReference(s, pairs)
+		got, n := NewMultiReplacer(pairs).Replace([]byte(s))
+		if string(got) != want || n != wantN {
+			t.Fatalf("Replace(%q) with %v = %q, %d; want %q, %d", s, pairs, got, n, want, wantN)
+		}
+	}
+}
+
+func TestMultiReplacerReturnsCopy(t *testing.T) {
+	s := []byte("nothing")
+	got, _ := NewMultiReplacer(map[string]string{"x": "y"}).Replace(s)
+	got[0] = 'N'
+	if s[0] != 'n' {
+		t.Errorf("Replace modified its input: %q", s)
+	}
+}
+
+// benchmarkDictionary 生成 size 个词的替换表和由这些词及干扰词组成的文本
+func benchmarkDictionary(size int) (map[string]string, []byte) {
+	rng := rand.New(rand.NewSource(42))
+	pairs := make(map[string]string, size)
+	words := make([]string, 0, size)
+	for len(words) < size {
+		w := fmt.Sprintf("w%x", rng.Int63())[:4+rng.Intn(8)]
+		if _, ok := pairs[w]; !ok {
+			pairs[w] = strings.ToUpper(w)
+			words = append(words, w)
+		}
+	}
+	var buf bytes.Buffer
+	for buf.Len() < 1<<20 {
+		if rng.Intn(2) == 0 {
+			buf.WriteString(words[rng.Intn(len(words))])
+		} else {
+			buf.WriteString("lorem ipsum")
+		}
+		buf.WriteByte(' ')
+	}
+	return pairs, buf.Bytes()
+}
+
+func BenchmarkMultiReplacer(b *testing.B) {
+	for _, size := range []int{10, 100, 1000, 10000} {
+		pairs, data := benchmarkDictionary(size)
+		text := string(data)
+
+		b.Run(fmt.Sprintf("AhoCorasick/%d", size), func(b *testing.B) {
+			m := NewMultiReplacer(pairs)
+			b.SetBytes(int64(len(data)))
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				m.Replace(data)
+			}
+		})
+
+		b.Run(fmt.Sprintf("StringsReplacer/%d", size), func(b *testing.B) {
+			// 按长度从长到短排列参数，strings.Replacer 的结果与最左最长规则一致
+			olds := make([]string, 0, len(pairs))
+			for old := range pairs {
+				olds = append(olds, old)
+			}
+			sort.Slice(olds, func(i, j int) bool { return len(olds[i]) > len(olds[j]) })
+			args := make([]string, 0, 2*len(olds))
+			for _, old := range olds {
+				args = append(args, old, pairs[old])
+			}
+			r := strings.NewReplacer(args...)
+			b.SetBytes(int64(len(text)))
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				r.Replace(text)
+			}
+		})
+
+		if size > 100 {
+			continue
+		}
+		b.Run(fmt.Sprintf("ChainedReplaceWithMode/%d", size), func(b *testing.B) {
+			b.SetBytes(int64(len(data)))
+			for i := 0; i < b.N; i++ {
+				s := data
+				for old, new := range pairs {
+					s = replaceWithMode(s, []byte(old), []byte(new), ReplaceAll())
+				}
+			}
+		})
+	}
+}
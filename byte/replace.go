@@ -0,0 +1,203 @@
+package byte
+
+import (
+	"bytes"
+	"fmt"
+	"unicode/utf8"
+)
+
+type replaceKind int
+
+const (
+	replaceAll replaceKind = iota
+	replaceFirst
+	replaceNone
+	replaceLast
+	replaceNth
+	replaceUpTo
+)
+
+// ReplaceMode 描述替换哪些匹配项。
+// 通过 ReplaceAll、ReplaceFirst、ReplaceNone、ReplaceLast、ReplaceNth、ReplaceUpTo 创建，
+// 这些函数每次返回新的值，其他包无法修改预定义的模式。零值等同于 ReplaceAll()。
+type ReplaceMode struct {
+	kind  replaceKind
+	n     int
+	flags ReplaceFlag
+}
+
+// ReplaceAll 替换所有匹配项。
+func ReplaceAll() ReplaceMode { return ReplaceMode{kind: replaceAll} }
+
+// ReplaceFirst 只替换第一个匹配项。
+func ReplaceFirst() ReplaceMode { return ReplaceMode{kind: replaceFirst} }
+
+// ReplaceNone 不做任何替换。
+func ReplaceNone() ReplaceMode { return ReplaceMode{kind: replaceNone} }
+
+// ReplaceLast 只替换最后一个匹配项（即 bytes.LastIndex 找到的位置）。
+func ReplaceLast() ReplaceMode { return ReplaceMode{kind: replaceLast} }
+
+// ReplaceNth 只替换从左到右第 n 个（从 1 开始）不重叠的匹配项，n < 1 时不替换。
+func ReplaceNth(n int) ReplaceMode {
+	return ReplaceMode{kind: replaceNth, n: n}
+}
+
+// ReplaceUpTo 最多替换前 n 个匹配项，与 bytes.Replace 一致，n < 0 表示不限制。
+func ReplaceUpTo(n int) ReplaceMode {
+	return ReplaceMode{kind: replaceUpTo, n: n}
+}
+
+// With 返回在 m 的基础上加上 flags 的替换模式。
+func (m ReplaceMode) With(flags ReplaceFlag) ReplaceMode {
+	m.flags |= flags
+	return m
+}
+
+func (m ReplaceMode) String() string {
+	return m.kindString() + m.flags.String()
+}
+
+func (m ReplaceMode) kindString() string {
+	switch m.kind {
+	case replaceAll:
+		return "ReplaceAll"
+	case replaceFirst:
+		return "ReplaceFirst"
+	case replaceNone:
+		return "ReplaceNone"
+	case replaceLast:
+		return "ReplaceLast"
+	case replaceNth:
+		return fmt.Sprintf("ReplaceNth(%d)", m.n)
+	case replaceUpTo:
+		return fmt.Sprintf("ReplaceUpTo(%d)", m.n)
+	}
+	return fmt.Sprintf("ReplaceMode(%d)", m.kind)
+}
+
+// Replace 根据指定的替换模式替换字节切片中的内容。
+// 参数：
+//
+//	s - 原始字节切片
+//	old - 要被替换的字节切片，为空时与 bytes.Replace 一样匹配开头和每个 UTF-8 字符之后的位置
+//	new - 替换成的字节切片
+//	mode - 替换模式
+//
+// 返回值：
+//
+//	返回替换后的新字节切片（总是副本）以及实际替换的次数
+func Replace(s, old, new []byte, mode ReplaceMode) ([]byte, int) {
+	matches := matchSpans(s, old, mode)
+	return applyReplacements(s, new, matches, mode.flags), len(matches)
+}
+
+// span 是一个匹配项在原始数据中的范围 [start, end)。
+// 忽略大小写时匹配到的内容长度可能与 old 不同。
+type span struct {
+	start, end int
+	occurrence int // 从左到右不重叠查找时的序号，从 1 开始；findLast 不计算序号
+}
+
+// matchSpans 返回 mode 选中的匹配项，按从左到右排列。
+func matchSpans(s, old []byte, mode ReplaceMode) []span {
+	m := newMatcher(old, mode.flags)
+	switch mode.kind {
+	case replaceAll:
+		return m.findAll(s, -1)
+	case replaceFirst:
+		return m.findAll(s, 1)
+	case replaceUpTo:
+		return m.findAll(s, mode.n)
+	case replaceNth:
+		if mode.n < 1 {
+			return nil
+		}
+		if all := m.findAll(s, mode.n); len(all) == mode.n {
+			return all[mode.n-1:]
+		}
+	case replaceLast:
+		if sp, ok := m.findLast(s); ok {
+			return []span{sp}
+		}
+	}
+	return nil
+}
+
+// findAll 从左到右查找最多 limit 个不重叠的匹配项，limit < 0 表示不限制。
+func (m *matcher) findAll(s []byte, limit int) []span {
+	var spans []span
+	for from := 0; limit < 0 || len(spans) < limit; {
+		sp, ok := m.next(s, from)
+		if !ok {
+			break
+		}
+		sp.occurrence = len(spans) + 1
+		spans = append(spans, sp)
+		var more bool
+		if from, more = resume(s, sp); !more {
+			break
+		}
+	}
+	return spans
+}
+
+// resume 返回匹配项 sp 之后继续查找的位置。
+// 空匹配之后要跳过一个字符，否则会在同一位置反复匹配；已经到达末尾时 ok 为 false。
+func resume(s []byte, sp span) (from int, ok bool) {
+	if sp.start != sp.end {
+		return sp.end, true
+	}
+	if sp.end == len(s) {
+		return 0, false
+	}
+	_, size := utf8.DecodeRune(s[sp.end:])
+	return sp.end + size, true
+}
+
+// next 返回从 from 开始的第一个匹配项。
+func (m *matcher) next(s []byte, from int) (span, bool) {
+	if m.flags == 0 {
+		i := m.search.Index(s[from:])
+		if i < 0 {
+			return span{}, false
+		}
+		return span{start: from + i, end: from + i + len(m.old)}, true
+	}
+	sp, _, ok := m.find(s, from, len(s)+1)
+	return sp, ok
+}
+
+// findLast 返回起点最靠右的匹配项，与 bytes.LastIndex 一样允许它与其他匹配项重叠。
+func (m *matcher) findLast(s []byte) (span, bool) {
+	if m.flags == 0 {
+		i := bytes.LastIndex(s, m.old)
+		return span{start: i, end: i + len(m.old)}, i >= 0
+	}
+	// 字符边界按从前往后解码的结果计算，无效字节处与从后往前解码的结果可能不同，
+	// 所以从前往后检查每个边界，记住最后一个匹配项
+	last, found := span{}, false
+	for i := 0; ; {
+		if end, ok := m.matchAt(s, i); ok {
+			last, found = span{start: i, end: end}, true
+		}
+		if i == len(s) {
+			return last, found
+		}
+		_, size := utf8.DecodeRune(s[i:])
+		i += size
+	}
+}
+
+// applyReplacements 把 s 中 matches 的各个范围替换为 new。
+// 设置了 IgnoreCase 时，new 会按照匹配内容的大小写形式调整。
+func applyReplacements(s, new []byte, matches []span, flags ReplaceFlag) []byte {
+	out := make([]byte, 0, len(s)+len(matches)*len(new))
+	last := 0
+	for _, sp := range matches {
+		out = append(out, s[last:sp.start]...)
+		out = appendReplacement(out, new, s[sp.start:sp.end], flags)
+		last = sp.end
+	}
+	return append(out, s[last:]...)
+}
@@ -0,0 +1,93 @@
+package byte
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestReplace(t *testing.T) {
+	tests := []struct {
+		s, old, new string
+		mode        ReplaceMode
+		want        string
+		count       int
+	}{
+		{"go gopher go", "go", "Go", ReplaceAll(), "Go Gopher Go", 3},
+		{"go gopher go", "go", "Go", ReplaceFirst(), "Go gopher go", 1},
+		{"go gopher go", "go", "Go", ReplaceNone(), "go gopher go", 0},
+		{"go gopher go", "go", "Go", ReplaceLast(), "go gopher Go", 1},
+		{"go gopher go", "go", "Go", ReplaceNth(2), "go Gopher go", 1},
+		{"go gopher go", "go", "Go", ReplaceNth(3), "go gopher Go", 1},
+		{"go gopher go", "go", "Go", ReplaceNth(4), "go gopher go", 0},
+		{"go gopher go", "go", "Go", ReplaceNth(0), "go gopher go", 0},
+		{"go gopher go", "go", "Go", ReplaceUpTo(2), "Go Gopher go", 2},
+		{"go gopher go", "go", "Go", ReplaceUpTo(10), "Go Gopher Go", 3},
+		{"go gopher go", "go", "Go", ReplaceUpTo(0), "go gopher go", 0},
+		{"go gopher go", "go", "Go", ReplaceUpTo(-1), "Go Gopher Go", 3},
+		{"hello world", "x", "y", ReplaceAll(), "hello world", 0},
+		{"hello world", "x", "y", ReplaceLast(), "hello world", 0},
+		// 匹配项不重叠，ReplaceLast 取最右边的位置
+		{"aaa", "aa", "b", ReplaceAll(), "ba", 1},
+		{"aaa", "aa", "b", ReplaceLast(), "ab", 1},
+		{"a-b-c", "-", "", ReplaceAll(), "abc", 2},
+		{"a-b-c", "-", "::", ReplaceNth(2), "a-b::c", 1},
+		// 空的 old 匹配开头和每个 UTF-8 字符之后
+		{"ab", "", "-", ReplaceAll(), "-a-b-", 3},
+		{"héllo", "", "|", ReplaceUpTo(3), "|h|é|llo", 3},
+		{"ab", "", "-", ReplaceLast(), "ab-", 1},
+		{"ab", "", "-", ReplaceNth(2), "a-b", 1},
+		{"", "", "x", ReplaceAll(), "x", 1},
+	}
+
+	for _, test := range tests {
+		got, n := Replace([]byte(test.s), []byte(test.old), []byte(test.new), test.mode)
+		if string(got) != test.want || n != test.count {
+			t.Errorf("Replace(%q, %q, %q, %v) = %q, %d; want %q, %d",
+				test.s, test.old, test.new, test.mode, got, n, test.want, test.count)
+		}
+	}
+}
+
+func TestReplaceMatchesBytesReplace(t *testing.T) {
+	// 长度不小于 64 的 old 会使用 Horspool 查找
+	long := strings.Repeat("ab", 33)
+	inputs := []string{"", "a", "aaaa", "abcabcab", "日本語日本", "x\xffy\xff", strings.Repeat("ab", 140) + "a"}
+	olds := []string{"", "a", "ab", "本", "\xff", long, long + "c"}
+	for _, s := range inputs {
+		for _, old := range olds {
+			for n := -1; n < 4; n++ {
+				want := bytes.Replace([]byte(s), []byte(old), []byte("<>"), n)
+				got, _ := Replace([]byte(s), []byte(old), []byte("<>"), ReplaceUpTo(n))
+				if !bytes.Equal(got, want) {
+					t.Errorf("Replace(%q, %q, ReplaceUpTo(%d)) = %q, want %q", s, old, n, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestReplaceReturnsCopy(t *testing.T) {
+	s := []byte("no match here")
+	got, _ := Replace(s, []byte("zzz"), []byte("y"), ReplaceAll())
+	got[0] = 'N'
+	if s[0] != 'n' {
+		t.Errorf("Replace modified its input: %q", s)
+	}
+}
+
+func TestReplaceModeString(t *testing.T) {
+	tests := map[ReplaceMode]string{
+		ReplaceAll():   "ReplaceAll",
+		ReplaceFirst(): "ReplaceFirst",
+		ReplaceNone():  "ReplaceNone",
+		ReplaceLast():  "ReplaceLast",
+		ReplaceNth(2):  "ReplaceNth(2)",
+		ReplaceUpTo(5): "ReplaceUpTo(5)",
+	}
+	for mode, want := range tests {
+		if got := mode.String(); got != want {
+			t.Errorf("String() = %q, want %q", got, want)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package byte
+
+import "unicode/utf8"
+
+// MatchContextSize 是 Match.Before 和 Match.After 最多包含的字节数，
+// 实际长度会缩短到 UTF-8 字符边界。
+const MatchContextSize = 32
+
+// Match 描述一个被替换模式选中的匹配项。
+// Text、Before 和 After 都与原始数据共享内存，不能修改。
+type Match struct {
+	Index      int    // 匹配项在原始数据中的起始字节位置
+	Occurrence int    // 从左到右不重叠查找时的序号，从 1 开始
+	Text       []byte // 匹配到的内容，设置了 IgnoreCase 时可能与 old 不同
+	Before     []byte // 匹配项之前的上下文
+	After      []byte // 匹配项之后的上下文
+}
+
+// End 返回匹配项之后第一个字节的位置。
+func (m Match) End() int {
+	return m.Index + len(m.Text)
+}
+
+// ReplaceFunc 与 Replace 相同，但每个选中的匹配项都替换为 fn 的返回值。
+// fn 按匹配项从左到右的顺序调用；设置了 IgnoreCase 时不会调整 fn 返回内容的大小写。
+func ReplaceFunc(s, old []byte, fn func(Match) []byte, mode ReplaceMode) ([]byte, int) {
+	matches := FindMatches(s, old, mode)
+	out := make([]byte, 0, len(s))
+	last := 0
+	for _, m := range matches {
+		out = append(out, s[last:m.Index]...)
+		out = append(out, fn(m)...)
+		last = m.End()
+	}
+	return append(out, s[last:]...), len(matches)
+}
+
+// FindMatches 返回 Replace 在同样参数下会替换的所有匹配项，不修改数据。
+func FindMatches(s, old []byte, mode ReplaceMode) []Match {
+	spans := matchSpans(s, old, mode)
+	if mode.kind == replaceLast && len(spans) == 1 {
+		spans[0].occurrence = lastOccurrence(s, old, mode, spans[0])
+	}
+	matches := make([]Match, len(spans))
+	for i, sp := range spans {
+		matches[i] = Match{
+			Index:      sp.start,
+			Occurrence: sp.occurrence,
+			Text:       s[sp.start:sp.end:sp.end],
+			Before:     contextBefore(s, sp.start),
+			After:      contextAfter(s, sp.end),
+		}
+	}
+	return matches
+}
+
+// lastOccurrence 计算 ReplaceLast 选中的匹配项的序号。它可能与前面的匹配项重叠，
+// 序号只计入在它之前结束的不重叠匹配项。
+func lastOccurrence(s, old []byte, mode ReplaceMode, last span) int {
+	n := 1
+	m := newMatcher(old, mode.flags)
+	for _, sp := range m.findAll(s, -1) {
+		if sp.start < last.start && sp.end <= last.start {
+			n++
+		}
+	}
+	return n
+}
+
+// contextBefore 返回 i 之前最多 MatchContextSize 个字节，不从字符中间开始。
+func contextBefore(s []byte, i int) []byte {
+	j := max(0, i-MatchContextSize)
+	for j < i && !utf8.RuneStart(s[j]) {
+		j++
+	}
+	return s[j:i:i]
+}
+
+// contextAfter 返回 i 之后最多 MatchContextSize 个字节，不在字符中间结束。
+func contextAfter(s []byte, i int) []byte {
+	j := min(len(s), i+MatchContextSize)
+	for j > i && j < len(s) && !utf8.RuneStart(s[j]) {
+		j--
+	}
+	return s[i:j:j]
+}
@@ -0,0 +1,100 @@
+package byte
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestReplaceFunc(t *testing.T) {
+	s := []byte("id=1 id=2 id=3")
+	got, n := ReplaceFunc(s, []byte("id"), func(m Match) []byte {
+		return []byte(fmt.Sprintf("ID%d@%d", m.Occurrence, m.Index))
+	}, ReplaceAll())
+	if want := "ID1@0=1 ID2@5=2 ID3@10=3"; string(got) != want || n != 3 {
+		t.Errorf("ReplaceFunc = %q, %d; want %q, 3", got, n, want)
+	}
+
+	// 按上下文决定是否脱敏：只处理 password= 之后的值
+	s = []byte("user=bob password=secret token=secret")
+	got, _ = ReplaceFunc(s, []byte("secret"), func(m Match) []byte {
+		if bytes.HasSuffix(m.Before, []byte("password=")) {
+			return []byte("******")
+		}
+		return m.Text
+	}, ReplaceAll())
+	if want := "user=bob password=****** token=secret"; string(got) != want {
+		t.Errorf("ReplaceFunc = %q, want %q", got, want)
+	}
+}
+
+func TestReplaceFuncMatchesReplace(t *testing.T) {
+	modes := []ReplaceMode{
+		ReplaceAll(), ReplaceFirst(), ReplaceNone(), ReplaceLast(), ReplaceNth(2), ReplaceUpTo(2),
+		ReplaceAll().With(WholeWord), ReplaceLast().With(RuneSafe),
+	}
+	for _, c := range streamCases {
+		for _, mode := range modes {
+			want, wantN := Replace([]byte(c.s), []byte(c.old), []byte(c.new), mode)
+			got, n := ReplaceFunc([]byte(c.s), []byte(c.old), func(Match) []byte { return []byte(c.new) }, mode)
+			if !bytes.Equal(got, want) || n != wantN {
+				t.Errorf("ReplaceFunc(%q, %q, %v) = %q, %d; want %q, %d", c.s, c.old, mode, got, n, want, wantN)
+			}
+		}
+	}
+}
+
+func TestFindMatches(t *testing.T) {
+	tests := []struct {
+		s, old string
+		mode   ReplaceMode
+		want   []Match
+	}{
+		{"go gopher go", "go", ReplaceAll(), []Match{
+			{Index: 0, Occurrence: 1, Text: []byte("go"), Before: []byte(""), After: []byte(" gopher go")},
+			{Index: 3, Occurrence: 2, Text: []byte("go"), Before: []byte("go "), After: []byte("pher go")},
+			{Index: 10, Occurrence: 3, Text: []byte("go"), Before: []byte("go gopher "), After: []byte("")},
+		}},
+		{"go gopher go", "go", ReplaceNth(2), []Match{
+			{Index: 3, Occurrence: 2, Text: []byte("go"), Before: []byte("go "), After: []byte("pher go")},
+		}},
+		{"go gopher go", "go", ReplaceLast(), []Match{
+			{Index: 10, Occurrence: 3, Text: []byte("go"), Before: []byte("go gopher "), After: []byte("")},
+		}},
+		// 最后一个匹配项与前一个重叠时不计入前一个
+		{"aaa", "aa", ReplaceLast(), []Match{
+			{Index: 1, Occurrence: 1, Text: []byte("aa"), Before: []byte("a"), After: []byte("")},
+		}},
+		{"Go GO", "go", ReplaceAll().With(IgnoreCase), []Match{
+			{Index: 0, Occurrence: 1, Text: []byte("Go"), Before: []byte(""), After: []byte(" GO")},
+			{Index: 3, Occurrence: 2, Text: []byte("GO"), Before: []byte("Go "), After: []byte("")},
+		}},
+		{"go gopher go", "go", ReplaceNone(), []Match{}},
+	}
+	for _, test := range tests {
+		got := FindMatches([]byte(test.s), []byte(test.old), test.mode)
+		if fmt.Sprintf("%q", got) != fmt.Sprintf("%q", test.want) {
+			t.Errorf("FindMatches(%q, %q, %v) = %q, want %q", test.s, test.old, test.mode, got, test.want)
+		}
+	}
+}
+
+func TestFindMatchesContext(t *testing.T) {
+	s := []byte(strings.Repeat("日本語", 10) + "needle" + strings.Repeat("日本語", 10))
+	matches := FindMatches(s, []byte("needle"), ReplaceAll())
+	if len(matches) != 1 {
+		t.Fatalf("FindMatches found %d matches, want 1", len(matches))
+	}
+	m := matches[0]
+	if len(m.Before) > MatchContextSize || len(m.After) > MatchContextSize {
+		t.Errorf("context too long: %d, %d bytes", len(m.Before), len(m.After))
+	}
+	// 上下文不拆分多字节字符
+	if !bytes.HasSuffix(m.Before, []byte("語")) || len(m.Before) != 30 || len(m.After) != 30 {
+		t.Errorf("Before = %q, After = %q", m.Before, m.After)
+	}
+	if m.End() != m.Index+len("needle") {
+		t.Errorf("End() = %d, want %d", m.End(), m.Index+len("needle"))
+	}
+}
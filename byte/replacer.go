@@ -0,0 +1,325 @@
+package byte
+
+import (
+	"bytes"
+	"io"
+	"unicode/utf8"
+
+	"go-libs-learning-kit/internal/wordbreak"
+)
+
+// Replacer 以流的方式执行与 Replace 相同的替换，写入的数据经过替换后写到底层 io.Writer。
+// 跨越两次 Write 的匹配项也能被正确识别：Replacer 只保留可能构成匹配前缀的末尾几个字节，
+// 其余数据立即写出。ReplaceLast 模式例外，它必须保留最后一个匹配项之后的全部数据，
+// 直到 Close 时才能确定它确实是最后一个；设置了 ReplaceFlag 的 ReplaceLast 会保留全部数据。
+//
+// 写完后必须调用 Close 写出剩余数据，Close 不会关闭底层 io.Writer。
+type Replacer struct {
+	w        io.Writer
+	old, new []byte
+	mode     ReplaceMode
+	search   Searcher // 没有设置 ReplaceFlag 时用于查找 old
+
+	buf   []byte // 尚未写出的数据
+	ctx   []byte // 已经写出的最后几个原始字节，设置了 WholeWord 时用于判断单词边界
+	out   []byte // 复用的输出缓冲区
+	work  []byte // scanFlagged 复用的 ctx+buf 缓冲区
+	seen  int    // 已经遇到的匹配项数量
+	count int    // 实际替换的次数
+	// emptyPending 表示当前位置是 old 为空时的一个匹配点（开头或刚处理完一个字符）
+	emptyPending bool
+	// held 表示 ReplaceLast 模式下 buf 以一个候选匹配项开头
+	held bool
+	// lastScanned 是 ReplaceLast 模式下 buf 中已经查找过的前缀长度，之后只查找新写入的数据
+	lastScanned int
+	err         error
+	closed      bool
+}
+
+// NewReplacer 创建一个把替换结果写入 w 的 Replacer，参数含义与 Replace 相同。
+func NewReplacer(w io.Writer, old, new []byte, mode ReplaceMode) *Replacer {
+	r := &Replacer{
+		w:            w,
+		old:          bytes.Clone(old),
+		new:          bytes.Clone(new),
+		mode:         mode,
+		emptyPending: true,
+	}
+	r.search = newSearcher(r.old, SearchAuto)
+	return r
+}
+
+// Write 把 p 交给 Replacer 处理，可以确定的部分会立即写到底层 io.Writer。
+// p 总是被全部接收，写到底层 io.Writer 失败时返回 len(p) 和该错误，调用方不应重试写入 p，
+// 之后的 Write 和 Close 都返回同一个错误。
+func (r *Replacer) Write(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	if r.closed {
+		return 0, io.ErrClosedPipe
+	}
+	r.buf = append(r.buf, p...)
+	return len(p), r.flush(false)
+}
+
+// Close 写出剩余的数据，之后不能再调用 Write。
+func (r *Replacer) Close() error {
+	if r.closed {
+		return r.err
+	}
+	r.closed = true
+	if r.err != nil {
+		return r.err
+	}
+	return r.flush(true)
+}
+
+// Count 返回到目前为止实际替换的次数。
+func (r *Replacer) Count() int {
+	return r.count
+}
+
+// active 判断后面的匹配项是否还可能被替换，不可能时数据可以直接透传。
+func (r *Replacer) active() bool {
+	switch r.mode.kind {
+	case replaceAll:
+		return true
+	case replaceFirst:
+		return r.seen < 1
+	case replaceUpTo:
+		return r.mode.n < 0 || r.seen < r.mode.n
+	case replaceNth:
+		return r.seen < r.mode.n
+	}
+	return false
+}
+
+// match 记录匹配到的内容 matched，并把替换后或原样的内容追加到输出中。
+func (r *Replacer) match(out, matched []byte) []byte {
+	r.seen++
+	if r.mode.kind == replaceNth && r.seen != r.mode.n {
+		return append(out, matched...)
+	}
+	r.count++
+	return appendReplacement(out, r.new, matched, r.mode.flags)
+}
+
+// flush 处理 buf 中能够确定结果的部分并写出，final 表示输入已经结束。
+func (r *Replacer) flush(final bool) error {
+	out := r.out[:0]
+	var consumed int
+	switch {
+	case r.mode.flags != 0:
+		out, consumed = r.scanFlagged(out, final)
+	case r.mode.kind == replaceLast:
+		out, consumed = r.scanLast(out, final)
+	case len(r.old) == 0:
+		out, consumed = r.scanEmpty(out, final)
+	default:
+		out, consumed = r.scan(out, final)
+	}
+	// ReplaceLast 模式下 buf 可能很大且经常没有可写出的部分，此时不移动数据
+	if consumed > 0 {
+		r.buf = append(r.buf[:0], r.buf[consumed:]...)
+	}
+	r.out = out
+
+	if len(out) == 0 {
+		return nil
+	}
+	n, err := r.w.Write(out)
+	if err == nil && n < len(out) {
+		err = io.ErrShortWrite
+	}
+	r.err = err
+	return err
+}
+
+// scan 从左到右查找不重叠的匹配项，返回输出和已经处理的字节数。
+func (r *Replacer) scan(out []byte, final bool) ([]byte, int) {
+	consumed := 0
+	for r.active() {
+		i := r.search.Index(r.buf[consumed:])
+		if i < 0 {
+			break
+		}
+		out = append(out, r.buf[consumed:consumed+i]...)
+		out = r.match(out, r.old)
+		consumed += i + len(r.old)
+	}
+	// 末尾不足 len(old) 的字节可能与后续数据组成匹配项，先留在 buf 中
+	end := len(r.buf)
+	if r.active() && !final {
+		end -= min(len(r.old)-1, end-consumed)
+	}
+	return append(out, r.buf[consumed:end]...), end
+}
+
+// scanEmpty 处理 old 为空的情况：开头和每个 UTF-8 字符之后都是匹配点，
+// 不完整的字符要等后续数据到来后再解码。
+func (r *Replacer) scanEmpty(out []byte, final bool) ([]byte, int) {
+	consumed := 0
+	for {
+		if r.emptyPending && r.active() {
+			out = r.match(out, nil)
+		}
+		r.emptyPending = false
+		rest := r.buf[consumed:]
+		if len(rest) == 0 {
+			return out, consumed
+		}
+		if !r.active() {
+			return append(out, rest...), len(r.buf)
+		}
+		if !final && !utf8.FullRune(rest) {
+			return out, consumed
+		}
+		_, size := utf8.DecodeRune(rest)
+		out = append(out, rest[:size]...)
+		consumed += size
+		r.emptyPending = true
+	}
+}
+
+// scanLast 只保留最后一个候选匹配项及其后的数据，与 bytes.LastIndex 一样允许匹配项重叠。
+// 每次只查找新写入的数据和之前末尾的 len(old)-1 个字节，总的查找量与输入长度成正比。
+func (r *Replacer) scanLast(out []byte, final bool) ([]byte, int) {
+	if len(r.old) == 0 {
+		// 空的 old 最后一次匹配在数据末尾
+		out = append(out, r.buf...)
+		if final {
+			out = r.match(out, nil)
+		}
+		return out, len(r.buf)
+	}
+
+	from := r.lastSearchStart()
+	if i := bytes.LastIndex(r.buf[from:], r.old); i >= 0 {
+		out = append(out, r.buf[:from+i]...)
+		r.buf = r.buf[from+i:]
+		r.held = true
+	}
+	r.lastScanned = len(r.buf)
+	switch {
+	case final && r.held:
+		out = r.match(out, r.old)
+		return append(out, r.buf[len(r.old):]...), len(r.buf)
+	case final:
+		return append(out, r.buf...), len(r.buf)
+	case r.held:
+		return out, 0
+	}
+	end := len(r.buf) - min(len(r.old)-1, len(r.buf))
+	r.lastScanned -= end
+	return append(out, r.buf[:end]...), end
+}
+
+// lastSearchStart 返回 scanLast 需要查找的起点：之前查找过的部分中只有末尾 len(old)-1 个字节
+// 可能与新数据组成匹配项，buf 开头的候选匹配项也不用再找
+func (r *Replacer) lastSearchStart() int {
+	from := max(0, r.lastScanned-len(r.old)+1)
+	if r.held {
+		from = max(from, 1)
+	}
+	return from
+}
+
+// flaggedContext 是 ctx 保留的字节数，也是末尾为判断单词边界额外保留的字节数，
+// 等于 wordbreak.IsBoundary 在边界两侧最多查看的范围
+const flaggedContext = wordbreak.Context
+
+// scanFlagged 用 matcher 处理设置了 ReplaceFlag 的模式。
+// 末尾保留的字节足以容纳最长的匹配项和判断单词边界时向后查看的字符，
+// 因此起点在保留区之前的候选位置，其匹配结果不会再随后续数据改变。
+func (r *Replacer) scanFlagged(out []byte, final bool) ([]byte, int) {
+	if r.mode.kind == replaceLast && !final {
+		// 直到输入结束才能确定最后一个匹配项，先不做任何处理
+		return out, 0
+	}
+	m := newMatcher(r.old, r.mode.flags)
+	r.work = append(append(r.work[:0], r.ctx...), r.buf...)
+	work := r.work
+	from := len(r.ctx)
+
+	if r.mode.kind == replaceLast {
+		if sp, ok := m.findLast(work); ok {
+			out = append(out, work[from:sp.start]...)
+			out = r.match(out, work[sp.start:sp.end])
+			from = sp.end
+		}
+		return append(out, work[from:]...), len(r.buf)
+	}
+
+	limit := len(work) + 1
+	if !final {
+		limit = len(work) - utf8.UTFMax*utf8.RuneCount(r.old) - flaggedContext
+	}
+	end := from
+	for {
+		if !r.active() {
+			end = len(work)
+			break
+		}
+		sp, next, ok := m.find(work, from, limit)
+		if !ok {
+			end = next
+			break
+		}
+		out = append(out, work[from:sp.start]...)
+		out = r.match(out, work[sp.start:sp.end])
+		from = sp.end
+		if sp.start == sp.end {
+			if from == len(work) {
+				end = from
+				break
+			}
+			_, size := utf8.DecodeRune(work[from:])
+			out = append(out, work[from:from+size]...)
+			from += size
+		}
+	}
+	out = append(out, work[from:end]...)
+	r.ctx = append(r.ctx[:0], work[max(0, end-flaggedContext):end]...)
+	return out, end - (len(work) - len(r.buf))
+}
+
+// ReplaceReader 从底层 io.Reader 读取数据，返回替换后的结果。
+type ReplaceReader struct {
+	src io.Reader
+	rep *Replacer
+	out bytes.Buffer
+	tmp []byte
+	err error
+}
+
+// NewReplaceReader 创建一个读取 src 并按 Replace 的规则替换内容的 io.Reader。
+func NewReplaceReader(src io.Reader, old, new []byte, mode ReplaceMode) *ReplaceReader {
+	rr := &ReplaceReader{src: src, tmp: make([]byte, 32*1024)}
+	rr.rep = NewReplacer(&rr.out, old, new, mode)
+	return rr
+}
+
+// Read 实现 io.Reader。底层 io.Reader 返回 io.EOF 以外的错误时，先返回已经确定的输出再返回该错误，
+// 为识别跨块匹配而保留的末尾数据不会输出。
+func (rr *ReplaceReader) Read(p []byte) (int, error) {
+	for rr.out.Len() == 0 && rr.err == nil {
+		n, err := rr.src.Read(rr.tmp)
+		// 写入 bytes.Buffer 不会失败
+		rr.rep.Write(rr.tmp[:n])
+		if err == io.EOF {
+			rr.rep.Close()
+		}
+		// 其他错误表示数据不完整，保留的末尾数据可能属于一个未读完的匹配，不能当作结尾输出
+		rr.err = err
+	}
+	if rr.out.Len() > 0 {
+		return rr.out.Read(p)
+	}
+	return 0, rr.err
+}
+
+// Count 返回到目前为止实际替换的次数。
+func (rr *ReplaceReader) Count() int {
+	return rr.rep.Count()
+}
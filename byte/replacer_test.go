@@ -0,0 +1,200 @@
+package byte
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+var streamModes = []ReplaceMode{
+	ReplaceAll(), ReplaceFirst(), ReplaceNone(), ReplaceLast(),
+	ReplaceNth(1), ReplaceNth(2), ReplaceNth(5), ReplaceUpTo(2), ReplaceUpTo(-1),
+	ReplaceAll().With(IgnoreCase), ReplaceAll().With(WholeWord), ReplaceAll().With(RuneSafe),
+	ReplaceNth(2).With(IgnoreCase | WholeWord), ReplaceLast().With(IgnoreCase), ReplaceFirst().With(RuneSafe),
+}
+
+var streamCases = []struct{ s, old, new string }{
+	{"go gopher go", "go", "Go"},
+	{"aaaaaaa", "aa", "b"},
+	{"abababab", "aba", "X"},
+	{"hello world", "xyz", "y"},
+	{"日本語の日本語", "日本", "にほん"},
+	{"a\xffb\xff", "\xff", "?"},
+	{"héllo", "", "|"},
+	{"", "", "|"},
+	{"", "go", "Go"},
+	{strings.Repeat("needle hay ", 500), "needle", "pin"},
+	{strings.Repeat("x"+strings.Repeat("0123456789abcdef", 5)+"y", 20), strings.Repeat("0123456789abcdef", 5), "<long>"},
+	{"Go gopher GO, go! gO \u212ao", "go", "rust"},
+	{strings.Repeat("Été été ÉTÉ étés ", 50), "été", "hiver"},
+	// 单词边界要跳过很长的附加字符序列，跨越多个数据块
+	{"x" + strings.Repeat("\u0301", 20) + "go go", "go", "Go"},
+	{"go'" + strings.Repeat("\u200d", 25) + "s go", "go", "Go"},
+	{"x" + strings.Repeat("\u0301", 40) + "go go", "go", "Go"},
+}
+
+// writeChunks 把 s 按 size 字节一段写入 Replacer
+func writeChunks(t *testing.T, s string, size int, old, new string, mode ReplaceMode) (string, int) {
+	t.Helper()
+	var out bytes.Buffer
+	r := NewReplacer(&out, []byte(old), []byte(new), mode)
+	for i := 0; i < len(s); i += size {
+		chunk := s[i:min(i+size, len(s))]
+		if n, err := r.Write([]byte(chunk)); n != len(chunk) || err != nil {
+			t.Fatalf("Write(%q) = %d, %v", chunk, n, err)
+		}
+	}
+	if err := r.Close(); err != nil {
+		t.Fatalf("Close() = %v", err)
+	}
+	return out.String(), r.Count()
+}
+
+func TestReplacerWriter(t *testing.T) {
+	for _, c := range streamCases {
+		for _, mode := range streamModes {
+			want, wantN := Replace([]byte(c.s), []byte(c.old), []byte(c.new), mode)
+			for _, size := range []int{1, 2, 3, 7, 4096} {
+				got, n := writeChunks(t, c.s, size, c.old, c.new, mode)
+				if got != string(want) || n != wantN {
+					t.Errorf("Replacer(%q, %q, %q, %v) chunk %d = %q, %d; want %q, %d",
+						c.s, c.old, c.new, mode, size, got, n, want, wantN)
+				}
+			}
+		}
+	}
+}
+
+func TestReplaceReader(t *testing.T) {
+	readers := map[string]func(io.Reader) io.Reader{
+		"OneByte": iotest.OneByteReader,
+		"Half":    iotest.HalfReader,
+		"DataErr": iotest.DataErrReader,
+	}
+	for _, c := range streamCases {
+		for _, mode := range streamModes {
+			want, wantN := Replace([]byte(c.s), []byte(c.old), []byte(c.new), mode)
+			for name, wrap := range readers {
+				rr := NewReplaceReader(wrap(strings.NewReader(c.s)), []byte(c.old), []byte(c.new), mode)
+				got, err := io.ReadAll(iotest.OneByteReader(rr))
+				if err != nil {
+					t.Fatalf("%s: ReadAll() = %v", name, err)
+				}
+				if string(got) != string(want) || rr.Count() != wantN {
+					t.Errorf("%s: ReplaceReader(%q, %q, %q, %v) = %q, %d; want %q, %d",
+						name, c.s, c.old, c.new, mode, got, rr.Count(), want, wantN)
+				}
+			}
+		}
+	}
+}
+
+func TestReplaceReaderIOTest(t *testing.T) {
+	s := strings.Repeat("go gopher go ", 100)
+	want, _ := Replace([]byte(s), []byte("go"), []byte("Go"), ReplaceAll())
+	rr := NewReplaceReader(iotest.OneByteReader(strings.NewReader(s)), []byte("go"), []byte("Go"), ReplaceAll())
+	if err := iotest.TestReader(rr, want); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestReplaceReaderError(t *testing.T) {
+	errBoom := errors.New("boom")
+	src := io.MultiReader(strings.NewReader("go go"), iotest.ErrReader(errBoom))
+	rr := NewReplaceReader(src, []byte("go"), []byte("Go"), ReplaceAll())
+	got, err := io.ReadAll(rr)
+	if !errors.Is(err, errBoom) {
+		t.Errorf("ReadAll() error = %v, want %v", err, errBoom)
+	}
+	// 出错前已经确定的输出仍然返回
+	if string(got) != "Go Go" {
+		t.Errorf("ReadAll() = %q, want %q", got, "Go Go")
+	}
+
+	// 保留的 "go" 可能属于一个未读完的匹配，出错时不能当作结尾输出
+	src = io.MultiReader(strings.NewReader("go gopher go"), iotest.ErrReader(errBoom))
+	rr = NewReplaceReader(src, []byte("go"), []byte("Go"), ReplaceAll().With(WholeWord))
+	got, err = io.ReadAll(rr)
+	if !errors.Is(err, errBoom) || strings.Contains(string(got), "Go") || !strings.HasPrefix("go gopher go", string(got)) {
+		t.Errorf("ReadAll() = %q, %v; want a prefix of the input without replacements and %v", got, err, errBoom)
+	}
+}
+
+type failingWriter struct{ err error }
+
+func (w failingWriter) Write(p []byte) (int, error) { return 0, w.err }
+
+func TestReplacerWriteError(t *testing.T) {
+	errBoom := errors.New("boom")
+	r := NewReplacer(failingWriter{errBoom}, []byte("go"), []byte("Go"), ReplaceAll())
+	// p 已经被接收，返回 len(p) 避免调用方重试时重复写入
+	if n, err := r.Write([]byte("go gopher")); n != len("go gopher") || !errors.Is(err, errBoom) {
+		t.Errorf("Write() = %d, %v; want %d, %v", n, err, len("go gopher"), errBoom)
+	}
+	if _, err := r.Write([]byte("more")); !errors.Is(err, errBoom) {
+		t.Errorf("second Write() error = %v, want %v", err, errBoom)
+	}
+	if err := r.Close(); !errors.Is(err, errBoom) {
+		t.Errorf("Close() error = %v, want %v", err, errBoom)
+	}
+}
+
+func TestReplacerWriteAfterClose(t *testing.T) {
+	r := NewReplacer(io.Discard, []byte("a"), []byte("b"), ReplaceAll())
+	r.Close()
+	if _, err := r.Write([]byte("a")); err == nil {
+		t.Error("Write after Close succeeded")
+	}
+}
+
+func TestReplacerBoundedBuffer(t *testing.T) {
+	var out bytes.Buffer
+	old := []byte("needle")
+	r := NewReplacer(&out, old, []byte("pin"), ReplaceAll())
+	for i := 0; i < 1000; i++ {
+		r.Write([]byte("hay hay needl"))
+		// 只保留可能成为匹配前缀的末尾字节
+		if len(r.buf) >= len(old) {
+			t.Fatalf("buffered %d bytes, want < %d", len(r.buf), len(old))
+		}
+	}
+}
+
+// ReplaceLast 模式要保留最后一个候选匹配项之后的全部数据，但每次 Write 只查找新数据
+func TestReplacerLastIncremental(t *testing.T) {
+	old := []byte("needle")
+	chunk := []byte(strings.Repeat("hay ", 16))
+	var out bytes.Buffer
+	r := NewReplacer(&out, old, []byte("pin"), ReplaceLast())
+	r.Write([]byte("needle "))
+	for i := 0; i < 4096; i++ {
+		r.Write(chunk)
+		// 下一次 Write 只需要查找新数据和之前末尾不足 len(old) 的字节
+		if rescan := len(r.buf) - r.lastSearchStart(); rescan >= len(old) {
+			t.Fatalf("write %d: next search rescans %d held bytes, want < %d", i, rescan, len(old))
+		}
+	}
+	r.Write([]byte("needl"))
+	r.Write([]byte("e hay"))
+	r.Close()
+	want := "needle " + strings.Repeat(string(chunk), 4096) + "pin hay"
+	if out.String() != want || r.Count() != 1 {
+		t.Errorf("ReplaceLast result differs, Count() = %d", r.Count())
+	}
+}
+
+func BenchmarkReplacer(b *testing.B) {
+	data := []byte(strings.Repeat("go gopher go gopher go ", 4096))
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		r := NewReplacer(io.Discard, []byte("go"), []byte("Go"), ReplaceAll())
+		for j := 0; j < len(data); j += 4096 {
+			r.Write(data[j:min(j+4096, len(data))])
+		}
+		r.Close()
+	}
+}
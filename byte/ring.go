@@ -0,0 +1,197 @@
+package byte
+
+import (
+	"errors"
+	"io"
+	"sync"
+)
+
+var (
+	// ErrRingFull 表示环形缓冲区已满，数据没有全部写入
+	ErrRingFull = errors.New("byte: ring buffer is full")
+	// ErrRingEmpty 表示非阻塞模式下环形缓冲区中没有数据可读
+	ErrRingEmpty = errors.New("byte: ring buffer is empty")
+)
+
+// FullPolicy 决定环形缓冲区写满后 Write 的行为
+type FullPolicy int
+
+const (
+	// FullBlock 等待读取方腾出空间；非阻塞模式下与 FullError 相同
+	FullBlock FullPolicy = iota
+	// FullError 写入能放下的部分后返回 ErrRingFull
+	FullError
+	// FullOverwrite 丢弃最早的数据，Write 总是全部写入；
+	// p 比缓冲区容量还大时只保留最后的部分
+	FullOverwrite
+)
+
+// RingOptions 是创建 RingBuffer 的选项
+type RingOptions struct {
+	// Blocking 为 true 时，没有数据时 Read、Peek 会等待写入，
+	// FullBlock 策略下写满时 Write 会等待读取
+	Blocking bool
+	Full     FullPolicy
+}
+
+// RingBuffer 是固定容量的环形字节缓冲区，实现了 io.Reader 和 io.Writer。
+// 它可以被一个写入 goroutine 和一个读取 goroutine 同时使用。
+type RingBuffer struct {
+	mu   sync.Mutex
+	cond sync.Cond // 数据、空间或关闭状态变化时广播
+
+	buf    []byte
+	r      int // 读取位置
+	n      int // 已有数据的长度
+	opts   RingOptions
+	closed bool
+}
+
+// NewRingBuffer 创建容量为 size 字节的环形缓冲区，size 必须为正数。
+func NewRingBuffer(size int, opts RingOptions) *RingBuffer {
+	if size <= 0 {
+		panic("byte: ring buffer size must be positive")
+	}
+	rb := &RingBuffer{buf: make([]byte, size), opts: opts}
+	rb.cond.L = &rb.mu
+	return rb
+}
+
+// Write 实现 io.Writer，写满后的行为由 FullPolicy 决定。关闭后返回 io.ErrClosedPipe。
+func (rb *RingBuffer) Write(p []byte) (int, error) {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+	if rb.closed {
+		return 0, io.ErrClosedPipe
+	}
+
+	if rb.opts.Full == FullOverwrite {
+		total := len(p)
+		if len(p) > len(rb.buf) {
+			p = p[len(p)-len(rb.buf):]
+		}
+		if over := len(p) - (len(rb.buf) - rb.n); over > 0 {
+			rb.skip(over)
+		}
+		rb.put(p)
+		rb.cond.Broadcast()
+		return total, nil
+	}
+
+	written := 0
+	for {
+		if k := rb.put(p[written:]); k > 0 {
+			written += k
+			rb.cond.Broadcast()
+		}
+		if written == len(p) {
+			return written, nil
+		}
+		if rb.opts.Full == FullError || !rb.opts.Blocking {
+			return written, ErrRingFull
+		}
+		rb.cond.Wait()
+		if rb.closed {
+			return written, io.ErrClosedPipe
+		}
+	}
+}
+
+// Read 实现 io.Reader。没有数据时，阻塞模式下等待写入，非阻塞模式下返回 ErrRingEmpty；
+// 关闭后读完剩余数据返回 io.EOF。
+func (rb *RingBuffer) Read(p []byte) (int, error) {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+	if err := rb.wait(len(p)); err != nil {
+		return 0, err
+	}
+	k := rb.get(p)
+	rb.skip(k)
+	rb.cond.Broadcast()
+	return k, nil
+}
+
+// Peek 与 Read 相同，但不移除读到的数据。
+func (rb *RingBuffer) Peek(p []byte) (int, error) {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+	if err := rb.wait(len(p)); err != nil {
+		return 0, err
+	}
+	return rb.get(p), nil
+}
+
+// Discard 丢弃最多 n 个字节，返回实际丢弃的字节数，不会等待。
+func (rb *RingBuffer) Discard(n int) int {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+	n = max(0, min(n, rb.n))
+	rb.skip(n)
+	if n > 0 {
+		rb.cond.Broadcast()
+	}
+	return n
+}
+
+// Close 关闭写入端：之后 Write 返回 io.ErrClosedPipe，读取方读完剩余数据后得到 io.EOF。
+// 正在等待的 Read 和 Write 都会被唤醒。
+func (rb *RingBuffer) Close() error {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+	rb.closed = true
+	rb.cond.Broadcast()
+	return nil
+}
+
+// Len 返回可读的字节数。
+func (rb *RingBuffer) Len() int {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+	return rb.n
+}
+
+// Cap 返回缓冲区容量。
+func (rb *RingBuffer) Cap() int {
+	return len(rb.buf)
+}
+
+// wait 等待至少有一个字节可读，want 为 0 时不等待。调用时必须持有锁。
+func (rb *RingBuffer) wait(want int) error {
+	for want > 0 && rb.n == 0 {
+		if rb.closed {
+			return io.EOF
+		}
+		if !rb.opts.Blocking {
+			return ErrRingEmpty
+		}
+		rb.cond.Wait()
+	}
+	return nil
+}
+
+// put 把 p 中能放下的部分追加到末尾，返回写入的字节数。
+func (rb *RingBuffer) put(p []byte) int {
+	k := min(len(p), len(rb.buf)-rb.n)
+	w := (rb.r + rb.n) % len(rb.buf)
+	c := copy(rb.buf[w:], p[:k])
+	copy(rb.buf, p[c:k])
+	rb.n += k
+	return k
+}
+
+// get 从读取位置开始复制最多 len(p) 个字节到 p，不移动读取位置。
+func (rb *RingBuffer) get(p []byte) int {
+	k := min(len(p), rb.n)
+	c := copy(p[:k], rb.buf[rb.r:])
+	copy(p[c:k], rb.buf)
+	return k
+}
+
+// skip 移除最早的 k 个字节。
+func (rb *RingBuffer) skip(k int) {
+	rb.r = (rb.r + k) % len(rb.buf)
+	rb.n -= k
+	if rb.n == 0 {
+		rb.r = 0
+	}
+}
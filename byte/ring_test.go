@@ -0,0 +1,191 @@
+package byte
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestRingBufferWrapAround(t *testing.T) {
+	rb := NewRingBuffer(8, RingOptions{Full: FullError})
+	p := make([]byte, 8)
+	for i := 0; i < 10; i++ {
+		// 每次写 5 字节读 5 字节，读写位置会多次越过末尾
+		in := []byte{byte(i), 1, 2, 3, 4}
+		if n, err := rb.Write(in); n != 5 || err != nil {
+			t.Fatalf("Write = %d, %v", n, err)
+		}
+		if n, err := rb.Read(p); n != 5 || err != nil || !bytes.Equal(p[:n], in) {
+			t.Fatalf("Read = %v, %d, %v; want %v", p[:n], n, err, in)
+		}
+	}
+}
+
+func TestRingBufferFullError(t *testing.T) {
+	rb := NewRingBuffer(4, RingOptions{Full: FullError})
+	n, err := rb.Write([]byte("abcdef"))
+	if n != 4 || !errors.Is(err, ErrRingFull) {
+		t.Errorf("Write = %d, %v; want 4, ErrRingFull", n, err)
+	}
+	got, _ := io.ReadAll(io.LimitReader(rb, 4))
+	if string(got) != "abcd" {
+		t.Errorf("Read %q, want %q", got, "abcd")
+	}
+}
+
+func TestRingBufferNonBlockingFullBlock(t *testing.T) {
+	// 非阻塞模式下 FullBlock 与 FullError 相同
+	rb := NewRingBuffer(2, RingOptions{Full: FullBlock})
+	if n, err := rb.Write([]byte("abc")); n != 2 || !errors.Is(err, ErrRingFull) {
+		t.Errorf("Write = %d, %v; want 2, ErrRingFull", n, err)
+	}
+}
+
+func TestRingBufferOverwrite(t *testing.T) {
+	rb := NewRingBuffer(4, RingOptions{Full: FullOverwrite})
+	rb.Write([]byte("abc"))
+	if n, err := rb.Write([]byte("de")); n != 2 || err != nil {
+		t.Fatalf("Write = %d, %v", n, err)
+	}
+	p := make([]byte, 8)
+	if n, _ := rb.Peek(p); string(p[:n]) != "bcde" {
+		t.Errorf("after overwrite got %q, want %q", p[:n], "bcde")
+	}
+	// 比容量大的写入只保留最后的部分
+	if n, err := rb.Write([]byte("0123456789")); n != 10 || err != nil {
+		t.Fatalf("Write = %d, %v", n, err)
+	}
+	if n, _ := rb.Read(p); string(p[:n]) != "6789" {
+		t.Errorf("after large overwrite got %q, want %q", p[:n], "6789")
+	}
+}
+
+func TestRingBufferPeekDiscard(t *testing.T) {
+	rb := NewRingBuffer(8, RingOptions{})
+	rb.Write([]byte("hello"))
+	p := make([]byte, 3)
+	if n, err := rb.Peek(p); n != 3 || err != nil || string(p) != "hel" {
+		t.Errorf("Peek = %q, %d, %v", p[:n], n, err)
+	}
+	if rb.Len() != 5 {
+		t.Errorf("Len after Peek = %d, want 5", rb.Len())
+	}
+	if n := rb.Discard(2); n != 2 {
+		t.Errorf("Discard(2) = %d", n)
+	}
+	if n := rb.Discard(10); n != 3 {
+		t.Errorf("Discard(10) = %d, want 3", n)
+	}
+	if n, err := rb.Read(p); n != 0 || !errors.Is(err, ErrRingEmpty) {
+		t.Errorf("Read on empty = %d, %v; want ErrRingEmpty", n, err)
+	}
+}
+
+func TestRingBufferClose(t *testing.T) {
+	rb := NewRingBuffer(8, RingOptions{Blocking: true})
+	rb.Write([]byte("tail"))
+	rb.Close()
+	if _, err := rb.Write([]byte("x")); !errors.Is(err, io.ErrClosedPipe) {
+		t.Errorf("Write after Close = %v, want io.ErrClosedPipe", err)
+	}
+	got, err := io.ReadAll(rb)
+	if string(got) != "tail" || err != nil {
+		t.Errorf("ReadAll = %q, %v; want %q", got, err, "tail")
+	}
+}
+
+func TestRingBufferBlocking(t *testing.T) {
+	rb := NewRingBuffer(4, RingOptions{Blocking: true})
+
+	// Read 等待写入
+	done := make(chan string)
+	go func() {
+		p := make([]byte, 4)
+		n, _ := rb.Read(p)
+		done <- string(p[:n])
+	}()
+	select {
+	case s := <-done:
+		t.Fatalf("Read returned %q before Write", s)
+	case <-time.After(20 * time.Millisecond):
+	}
+	rb.Write([]byte("hi"))
+	if s := <-done; s != "hi" {
+		t.Errorf("Read = %q, want %q", s, "hi")
+	}
+
+	// Write 等待读取腾出空间，Close 唤醒等待中的 Write
+	rb.Write([]byte("1234"))
+	errc := make(chan error)
+	go func() {
+		_, err := rb.Write([]byte("5"))
+		errc <- err
+	}()
+	select {
+	case err := <-errc:
+		t.Fatalf("Write returned %v while full", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+	rb.Close()
+	if err := <-errc; !errors.Is(err, io.ErrClosedPipe) {
+		t.Errorf("blocked Write after Close = %v, want io.ErrClosedPipe", err)
+	}
+}
+
+func TestRingBufferProducerConsumer(t *testing.T) {
+	rb := NewRingBuffer(61, RingOptions{Blocking: true})
+	data := make([]byte, 1<<20)
+	rand.New(rand.NewSource(1)).Read(data)
+
+	go func() {
+		rng := rand.New(rand.NewSource(2))
+		for rest := data; len(rest) > 0; {
+			k := min(len(rest), 1+rng.Intn(100))
+			if n, err := rb.Write(rest[:k]); n != k || err != nil {
+				t.Errorf("Write = %d, %v", n, err)
+				return
+			}
+			rest = rest[k:]
+		}
+		rb.Close()
+	}()
+
+	var got bytes.Buffer
+	rng := rand.New(rand.NewSource(3))
+	p := make([]byte, 128)
+	for {
+		n, err := rb.Read(p[:1+rng.Intn(len(p))])
+		got.Write(p[:n])
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !bytes.Equal(got.Bytes(), data) {
+		t.Errorf("consumer received %d bytes, different from the %d produced", got.Len(), len(data))
+	}
+}
+
+func BenchmarkRingBuffer(b *testing.B) {
+	rb := NewRingBuffer(64<<10, RingOptions{Blocking: true})
+	chunk := make([]byte, 4096)
+	go func() {
+		p := make([]byte, 4096)
+		for {
+			if _, err := rb.Read(p); err != nil {
+				return
+			}
+		}
+	}()
+	b.SetBytes(int64(len(chunk)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		rb.Write(chunk)
+	}
+	rb.Close()
+}
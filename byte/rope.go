@@ -0,0 +1,371 @@
+package byte
+
+import (
+	"bytes"
+	"errors"
+	"io"
+)
+
+// ErrRopeRange 表示行号或列号超出文本范围
+var ErrRopeRange = errors.New("byte: rope position out of range")
+
+// ropeLeafSize 是叶子节点的最大字节数，相邻的小叶子在拼接时会合并
+const ropeLeafSize = 2048
+
+// ropeNode 是 rope 的节点，创建后不再修改，因此修改前的快照可以安全地继续读取。
+// 叶子节点只有 leaf，内部节点只有 left、right。
+type ropeNode struct {
+	left, right *ropeNode
+	leaf        []byte
+	length      int // 子树中的字节数
+	lines       int // 子树中 '\n' 的数量
+	height      int // 叶子高度为 1
+}
+
+// Rope 是面向文本编辑的字节序列，插入和删除的代价为 O(log n)，不随插入位置移动数据。
+// 内部是按高度平衡（AVL）的二叉树，每个节点记录子树的长度和换行数，
+// 因此按行号查找和计算行列号也是 O(log n)。
+// 零值是空的 Rope。Rope 不能被多个 goroutine 同时修改，
+// 但 NewReader 返回的 Reader 读取的是创建时的快照，不受之后修改的影响。
+type Rope struct {
+	root *ropeNode
+}
+
+// NewRope 创建内容为 data 副本的 Rope。
+func NewRope(data []byte) *Rope {
+	return &Rope{root: buildRope(data)}
+}
+
+// Len 返回字节数。
+func (r *Rope) Len() int {
+	return r.root.len()
+}
+
+// LineCount 返回行数，即换行符数量加一，空文本有一行。
+func (r *Rope) LineCount() int {
+	if r.root == nil {
+		return 1
+	}
+	return r.root.lines + 1
+}
+
+// Insert 在 pos 处插入 data 的副本，pos 必须在 [0, Len()] 范围内。
+func (r *Rope) Insert(pos int, data []byte) {
+	r.checkRange(pos, pos)
+	if len(data) == 0 {
+		return
+	}
+	left, right := splitRope(r.root, pos)
+	r.root = joinRope(joinRope(left, buildRope(data)), right)
+}
+
+// Delete 删除 [start, end) 范围内的内容，范围必须满足 0 <= start <= end <= Len()。
+func (r *Rope) Delete(start, end int) {
+	r.checkRange(start, end)
+	if start == end {
+		return
+	}
+	left, rest := splitRope(r.root, start)
+	_, right := splitRope(rest, end-start)
+	r.root = joinRope(left, right)
+}
+
+// Slice 返回 [start, end) 范围内容的副本。
+func (r *Rope) Slice(start, end int) []byte {
+	r.checkRange(start, end)
+	out := make([]byte, 0, end-start)
+	r.root.walk(start, end, func(b []byte) { out = append(out, b...) })
+	return out
+}
+
+// Bytes 返回全部内容的副本。
+func (r *Rope) Bytes() []byte {
+	return r.Slice(0, r.Len())
+}
+
+func (r *Rope) String() string {
+	return string(r.Bytes())
+}
+
+// WriteTo 实现 io.WriterTo，按叶子节点依次写出全部内容。
+func (r *Rope) WriteTo(w io.Writer) (int64, error) {
+	return r.NewReader().WriteTo(w)
+}
+
+// Offset 返回第 line 行第 col 列的字节偏移量，行号和列号都从 0 开始，列以字节计。
+// col 可以等于该行的长度（不含换行符），表示行尾。
+func (r *Rope) Offset(line, col int) (int, error) {
+	if line < 0 || line >= r.LineCount() || col < 0 {
+		return 0, ErrRopeRange
+	}
+	start := r.lineStart(line)
+	end := r.Len()
+	if line+1 < r.LineCount() {
+		end = r.lineStart(line+1) - 1
+	}
+	if col > end-start {
+		return 0, ErrRopeRange
+	}
+	return start + col, nil
+}
+
+// LineCol 返回字节偏移量 offset 所在的行号和列号，offset 必须在 [0, Len()] 范围内。
+func (r *Rope) LineCol(offset int) (line, col int) {
+	r.checkRange(offset, offset)
+	line = r.root.countLines(offset)
+	return line, offset - r.lineStart(line)
+}
+
+// lineStart 返回第 line 行开头的偏移量，即第 line 个换行符之后的位置。
+func (r *Rope) lineStart(line int) int {
+	if line == 0 {
+		return 0
+	}
+	return r.root.afterNewline(line)
+}
+
+func (r *Rope) checkRange(start, end int) {
+	if start < 0 || start > end || end > r.Len() {
+		panic("byte: rope range out of bounds")
+	}
+}
+
+// NewReader 返回读取当前内容的 RopeReader，之后对 Rope 的修改不会影响它。
+func (r *Rope) NewReader() *RopeReader {
+	rr := &RopeReader{}
+	rr.push(r.root)
+	return rr
+}
+
+// RopeReader 按顺序读取 Rope 某一时刻的内容，实现了 io.Reader 和 io.WriterTo。
+type RopeReader struct {
+	stack []*ropeNode // 待访问的子树，栈顶是下一个
+	cur   []byte      // 当前叶子中未读的部分
+}
+
+func (rr *RopeReader) push(n *ropeNode) {
+	if n != nil {
+		rr.stack = append(rr.stack, n)
+	}
+}
+
+// next 返回下一个叶子的数据，没有时返回 nil。
+func (rr *RopeReader) next() []byte {
+	for len(rr.stack) > 0 {
+		n := rr.stack[len(rr.stack)-1]
+		rr.stack = rr.stack[:len(rr.stack)-1]
+		if n.leaf != nil {
+			return n.leaf
+		}
+		rr.push(n.right)
+		rr.push(n.left)
+	}
+	return nil
+}
+
+// Read 实现 io.Reader。
+func (rr *RopeReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if len(rr.cur) == 0 {
+		if rr.cur = rr.next(); rr.cur == nil {
+			return 0, io.EOF
+		}
+	}
+	n := copy(p, rr.cur)
+	rr.cur = rr.cur[n:]
+	return n, nil
+}
+
+// WriteTo 实现 io.WriterTo，写出剩余的全部内容。
+func (rr *RopeReader) WriteTo(w io.Writer) (int64, error) {
+	var total int64
+	b := rr.cur
+	if len(b) == 0 {
+		b = rr.next()
+	}
+	for ; b != nil; b = rr.next() {
+		n, err := w.Write(b)
+		total += int64(n)
+		if err != nil {
+			rr.cur = b[n:]
+			return total, err
+		}
+	}
+	rr.cur = nil
+	return total, nil
+}
+
+func (n *ropeNode) len() int {
+	if n == nil {
+		return 0
+	}
+	return n.length
+}
+
+func (n *ropeNode) depth() int {
+	if n == nil {
+		return 0
+	}
+	return n.height
+}
+
+// newLeaf 创建叶子节点，调用方保证 data 之后不会被修改。
+func newLeaf(data []byte) *ropeNode {
+	if len(data) == 0 {
+		return nil
+	}
+	return &ropeNode{leaf: data, length: len(data), lines: bytes.Count(data, []byte{'\n'}), height: 1}
+}
+
+func newNode(left, right *ropeNode) *ropeNode {
+	return &ropeNode{
+		left:   left,
+		right:  right,
+		length: left.length + right.length,
+		lines:  left.lines + right.lines,
+		height: 1 + max(left.height, right.height),
+	}
+}
+
+// buildRope 把 data 的副本切成叶子，自底向上构建平衡的树。
+func buildRope(data []byte) *ropeNode {
+	if len(data) == 0 {
+		return nil
+	}
+	data = bytes.Clone(data)
+	nodes := make([]*ropeNode, 0, (len(data)+ropeLeafSize-1)/ropeLeafSize)
+	for len(data) > 0 {
+		k := min(len(data), ropeLeafSize)
+		nodes = append(nodes, newLeaf(data[:k:k]))
+		data = data[k:]
+	}
+	for len(nodes) > 1 {
+		var parents []*ropeNode
+		for i := 0; i < len(nodes); i += 2 {
+			if i+1 < len(nodes) {
+				parents = append(parents, newNode(nodes[i], nodes[i+1]))
+			} else {
+				parents = append(parents, nodes[i])
+			}
+		}
+		nodes = parents
+	}
+	return nodes[0]
+}
+
+// joinRope 拼接两棵树并保持高度平衡，高度相差 h 时代价为 O(h)。
+func joinRope(l, r *ropeNode) *ropeNode {
+	switch {
+	case l == nil:
+		return r
+	case r == nil:
+		return l
+	case l.leaf != nil && r.leaf != nil && l.length+r.length <= ropeLeafSize:
+		// 合并小叶子时分配新数组，不能 append 到可能被其他快照共享的 l.leaf 上
+		data := make([]byte, 0, l.length+r.length)
+		return newLeaf(append(append(data, l.leaf...), r.leaf...))
+	case l.height > r.height+1:
+		return rebalance(l.left, joinRope(l.right, r))
+	case r.height > l.height+1:
+		return rebalance(joinRope(l, r.left), r.right)
+	}
+	return newNode(l, r)
+}
+
+// rebalance 创建以 left、right 为子树的节点，两者高度相差 2 时通过旋转恢复平衡。
+func rebalance(left, right *ropeNode) *ropeNode {
+	switch {
+	case left.depth() > right.depth()+1:
+		if left.left.depth() < left.right.depth() {
+			// 先左旋 left
+			lr := left.right
+			left = newNode(newNode(left.left, lr.left), lr.right)
+		}
+		return newNode(left.left, newNode(left.right, right))
+	case right.depth() > left.depth()+1:
+		if right.right.depth() < right.left.depth() {
+			rl := right.left
+			right = newNode(rl.left, newNode(rl.right, right.right))
+		}
+		return newNode(newNode(left, right.left), right.right)
+	}
+	return joinRope(left, right)
+}
+
+// splitRope 在偏移量 i 处把树分成两棵。
+func splitRope(n *ropeNode, i int) (*ropeNode, *ropeNode) {
+	switch {
+	case n == nil:
+		return nil, nil
+	case i == 0:
+		return nil, n
+	case i == n.length:
+		return n, nil
+	case n.leaf != nil:
+		return newLeaf(n.leaf[:i:i]), newLeaf(n.leaf[i:])
+	case i <= n.left.length:
+		ll, lr := splitRope(n.left, i)
+		return ll, joinRope(lr, n.right)
+	}
+	rl, rr := splitRope(n.right, i-n.left.length)
+	return joinRope(n.left, rl), rr
+}
+
+// walk 按顺序对 [start, end) 范围内的每段叶子数据调用 fn。
+func (n *ropeNode) walk(start, end int, fn func([]byte)) {
+	if n == nil || start >= end {
+		return
+	}
+	if n.leaf != nil {
+		fn(n.leaf[start:end])
+		return
+	}
+	if start < n.left.length {
+		n.left.walk(start, min(end, n.left.length), fn)
+	}
+	if end > n.left.length {
+		n.right.walk(max(0, start-n.left.length), end-n.left.length, fn)
+	}
+}
+
+// countLines 返回 [0, i) 范围内的换行符数量。
+func (n *ropeNode) countLines(i int) int {
+	count := 0
+	for n != nil {
+		if n.leaf != nil {
+			return count + bytes.Count(n.leaf[:i], []byte{'\n'})
+		}
+		if i <= n.left.length {
+			n = n.left
+			continue
+		}
+		count += n.left.lines
+		i -= n.left.length
+		n = n.right
+	}
+	return count
+}
+
+// afterNewline 返回第 k 个（从 1 开始）换行符之后的偏移量，调用方保证 1 <= k <= n.lines。
+func (n *ropeNode) afterNewline(k int) int {
+	offset := 0
+	for n.leaf == nil {
+		if k <= n.left.lines {
+			n = n.left
+			continue
+		}
+		k -= n.left.lines
+		offset += n.left.length
+		n = n.right
+	}
+	for i, c := range n.leaf {
+		if c == '\n' {
+			if k--; k == 0 {
+				return offset + i + 1
+			}
+		}
+	}
+	panic("byte: rope line index is inconsistent")
+}
@@ -0,0 +1,258 @@
+package byte
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// checkRope 检查节点记录的长度、换行数和高度，以及 AVL 平衡条件
+func checkRope(t *testing.T, n *ropeNode) {
+	t.Helper()
+	if n == nil {
+		return
+	}
+	if n.leaf != nil {
+		if n.length != len(n.leaf) || n.lines != bytes.Count(n.leaf, []byte{'\n'}) || n.height != 1 || len(n.leaf) == 0 {
+			t.Fatalf("bad leaf %+v", n)
+		}
+		return
+	}
+	checkRope(t, n.left)
+	checkRope(t, n.right)
+	if n.length != n.left.length+n.right.length || n.lines != n.left.lines+n.right.lines {
+		t.Fatalf("bad node counts: %d/%d", n.length, n.lines)
+	}
+	if n.height != 1+max(n.left.height, n.right.height) {
+		t.Fatalf("bad node height %d", n.height)
+	}
+	if d := n.left.height - n.right.height; d < -1 || d > 1 {
+		t.Fatalf("unbalanced node: left %d, right %d", n.left.height, n.right.height)
+	}
+}
+
+func TestRopeRandomEdits(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	var model []byte
+	r := NewRope(nil)
+	alphabet := []byte("abc\n日")
+	for i := 0; i < 3000; i++ {
+		switch op := rng.Intn(10); {
+		case op < 6:
+			pos := rng.Intn(len(model) + 1)
+			data := make([]byte, rng.Intn(3000))
+			for j := range data {
+				data[j] = alphabet[rng.Intn(len(alphabet))]
+			}
+			r.Insert(pos, data)
+			model = append(model[:pos:pos], append(data, model[pos:]...)...)
+		default:
+			start := rng.Intn(len(model) + 1)
+			end := start + rng.Intn(len(model)-start+1)
+			r.Delete(start, end)
+			model = append(model[:start:start], model[end:]...)
+		}
+		if i%50 == 0 {
+			checkRope(t, r.root)
+		}
+		if r.Len() != len(model) || r.LineCount() != bytes.Count(model, []byte{'\n'})+1 {
+			t.Fatalf("step %d: Len %d, LineCount %d; model %d bytes", i, r.Len(), r.LineCount(), len(model))
+		}
+		start := rng.Intn(len(model) + 1)
+		end := start + rng.Intn(len(model)-start+1)
+		if got := r.Slice(start, end); !bytes.Equal(got, model[start:end]) {
+			t.Fatalf("step %d: Slice(%d, %d) differs from model", i, start, end)
+		}
+	}
+	checkRope(t, r.root)
+	if !bytes.Equal(r.Bytes(), model) {
+		t.Fatal("Bytes() differs from model")
+	}
+}
+
+func TestRopeLineCol(t *testing.T) {
+	text := "first\nsecond line\n\nlast"
+	r := NewRope([]byte(text))
+	if r.LineCount() != 4 {
+		t.Fatalf("LineCount = %d, want 4", r.LineCount())
+	}
+	for offset := 0; offset <= len(text); offset++ {
+		line := strings.Count(text[:offset], "\n")
+		col := offset - (strings.LastIndex(text[:offset], "\n") + 1)
+		if l, c := r.LineCol(offset); l != line || c != col {
+			t.Errorf("LineCol(%d) = %d, %d; want %d, %d", offset, l, c, line, col)
+		}
+		if got, err := r.Offset(line, col); got != offset || err != nil {
+			t.Errorf("Offset(%d, %d) = %d, %v; want %d", line, col, got, err, offset)
+		}
+	}
+	for _, lc := range [][2]int{{-1, 0}, {4, 0}, {0, 6}, {2, 1}, {3, 5}, {1, -1}} {
+		if _, err := r.Offset(lc[0], lc[1]); !errors.Is(err, ErrRopeRange) {
+			t.Errorf("Offset(%d, %d) error = %v, want ErrRopeRange", lc[0], lc[1], err)
+		}
+	}
+}
+
+func TestRopeLineColLarge(t *testing.T) {
+	var sb strings.Builder
+	for i := 0; i < 5000; i++ {
+		sb.WriteString(strings.Repeat("x", i%80))
+		sb.WriteByte('\n')
+	}
+	text := sb.String()
+	r := NewRope([]byte(text))
+	r.Insert(len(text)/2, []byte("inserted\n"))
+	text = text[:len(text)/2] + "inserted\n" + text[len(text)/2:]
+	for _, line := range []int{0, 1, 2500, 2501, 4999, 5000, 5001} {
+		want := 0
+		for k := 0; k < line; k++ {
+			want += strings.Index(text[want:], "\n") + 1
+		}
+		if got, err := r.Offset(line, 0); got != want || err != nil {
+			t.Errorf("Offset(%d, 0) = %d, %v; want %d", line, got, err, want)
+		}
+	}
+}
+
+func TestRopeReader(t *testing.T) {
+	data := []byte(strings.Repeat("rope reader test\n", 1000))
+	r := NewRope(data)
+	if err := iotest.TestReader(r.NewReader(), data); err != nil {
+		t.Error(err)
+	}
+
+	// Reader 读取创建时的快照
+	rd := r.NewReader()
+	r.Delete(0, r.Len())
+	var buf bytes.Buffer
+	if n, err := buf.ReadFrom(rd); err != nil || n != int64(len(data)) || !bytes.Equal(buf.Bytes(), data) {
+		t.Errorf("snapshot read %d bytes, %v", n, err)
+	}
+
+	buf.Reset()
+	r.Insert(0, []byte("new"))
+	if _, err := r.WriteTo(&buf); err != nil || buf.String() != "new" {
+		t.Errorf("WriteTo = %q, %v", buf.String(), err)
+	}
+}
+
+func TestRopeInsertCopiesData(t *testing.T) {
+	data := []byte("abc")
+	r := NewRope(data)
+	r.Insert(1, data)
+	data[0] = 'X'
+	if got := r.String(); got != "aabcbc" {
+		t.Errorf("String() = %q, want %q", got, "aabcbc")
+	}
+}
+
+func TestRopeRangePanics(t *testing.T) {
+	r := NewRope([]byte("abc"))
+	for name, fn := range map[string]func(){
+		"Insert": func() { r.Insert(4, []byte("x")) },
+		"Delete": func() { r.Delete(2, 1) },
+		"Slice":  func() { r.Slice(-1, 2) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s did not panic", name)
+				}
+			}()
+			fn()
+		}()
+	}
+}
+
+// ropeBenchDoc 生成约 1MB、每行 64 字节的文档
+func ropeBenchDoc() []byte {
+	return bytes.Repeat([]byte(strings.Repeat("x", 63)+"\n"), 1<<14)
+}
+
+func BenchmarkRopeInsert(b *testing.B) {
+	doc := ropeBenchDoc()
+	text := []byte("typed ")
+
+	b.Run("Rope", func(b *testing.B) {
+		r := NewRope(doc)
+		rng := rand.New(rand.NewSource(1))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			r.Insert(rng.Intn(r.Len()+1), text)
+		}
+	})
+
+	b.Run("BytesBuffer", func(b *testing.B) {
+		var buf bytes.Buffer
+		buf.Write(doc)
+		rng := rand.New(rand.NewSource(1))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			pos := rng.Intn(buf.Len() + 1)
+			tail := bytes.Clone(buf.Bytes()[pos:])
+			buf.Truncate(pos)
+			buf.Write(text)
+			buf.Write(tail)
+		}
+	})
+}
+
+func BenchmarkRopeDelete(b *testing.B) {
+	doc := ropeBenchDoc()
+
+	b.Run("Rope", func(b *testing.B) {
+		r := NewRope(doc)
+		rng := rand.New(rand.NewSource(1))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if r.Len() < 1024 {
+				r = NewRope(doc)
+			}
+			pos := rng.Intn(r.Len() - 8)
+			r.Delete(pos, pos+8)
+		}
+	})
+
+	b.Run("BytesBuffer", func(b *testing.B) {
+		var buf bytes.Buffer
+		buf.Write(doc)
+		rng := rand.New(rand.NewSource(1))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if buf.Len() < 1024 {
+				buf.Reset()
+				buf.Write(doc)
+			}
+			pos := rng.Intn(buf.Len() - 8)
+			tail := bytes.Clone(buf.Bytes()[pos+8:])
+			buf.Truncate(pos)
+			buf.Write(tail)
+		}
+	})
+}
+
+func BenchmarkRopeLineOffset(b *testing.B) {
+	doc := ropeBenchDoc()
+	lines := bytes.Count(doc, []byte{'\n'})
+
+	b.Run("Rope", func(b *testing.B) {
+		r := NewRope(doc)
+		for i := 0; i < b.N; i++ {
+			r.Offset(i%lines, 10)
+		}
+	})
+
+	b.Run("BytesBuffer", func(b *testing.B) {
+		buf := bytes.NewBuffer(doc)
+		for i := 0; i < b.N; i++ {
+			data, offset := buf.Bytes(), 0
+			for k := i % lines; k > 0; k-- {
+				offset += bytes.IndexByte(data[offset:], '\n') + 1
+			}
+			_ = offset + 10
+		}
+	})
+}
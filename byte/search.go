@@ -0,0 +1,304 @@
+package byte
+
+import "bytes"
+
+// SearchAlgorithm 是 Searcher 使用的子串查找算法
+type SearchAlgorithm int
+
+const (
+	// SearchAuto 按模式长度选择算法，见 NewSearcher
+	SearchAuto SearchAlgorithm = iota
+	// SearchStdlib 直接使用 bytes.Index，短模式时它利用汇编实现的 IndexByte 最快
+	SearchStdlib
+	// SearchHorspool 是 Boyer-Moore-Horspool 算法，按模式末尾字节的坏字符表跳跃，
+	// 长模式时平均每次跳过接近模式长度的字节，最坏 O(nm)
+	SearchHorspool
+	// SearchTwoWay 是 Crochemore-Perrin 双向算法，最坏 O(n+m)，只用常数额外空间，
+	// 适合高度重复、容易让其他算法退化的数据
+	SearchTwoWay
+	// SearchRabinKarp 用滚动哈希比较每个窗口，期望 O(n+m)，速度与模式长度无关
+	SearchRabinKarp
+)
+
+// autoHorspoolLen 是 SearchAuto 改用 Horspool 的最小模式长度。
+// 在日志数据上的测试（BenchmarkSearch）中，bytes.Index 先用汇编实现的 IndexByte 查找模式的首字节，
+// 首字节在文本中少见时很快，常见时（如 "request_id=..."）退化为逐个候选位置比较；
+// Horspool 的速度只取决于跳跃距离，模式达到 64 字节后，首字节少见时与 bytes.Index 相差不到两成，
+// 首字节常见时快两倍以上，128 字节时快四倍以上。
+const autoHorspoolLen = 64
+
+func (a SearchAlgorithm) String() string {
+	switch a {
+	case SearchAuto:
+		return "Auto"
+	case SearchStdlib:
+		return "Stdlib"
+	case SearchHorspool:
+		return "Horspool"
+	case SearchTwoWay:
+		return "TwoWay"
+	case SearchRabinKarp:
+		return "RabinKarp"
+	}
+	return "SearchAlgorithm(?)"
+}
+
+// Searcher 在文本中查找固定的模式，预处理的结果可以在多次查找之间复用。
+// 创建后只读，可以被多个 goroutine 同时使用。
+type Searcher struct {
+	pattern []byte
+	algo    SearchAlgorithm
+
+	// skip 是 Horspool 的跳跃表，直接放在结构体中，创建 Searcher 不需要单独分配。
+	// 跳跃距离超过 255 时按 255 保存，长模式只是少跳一些，结果不变
+	skip [256]uint8
+	// guard 为 true 时 Horspool 遇到过多候选位置会改用 bytes.Index，避免在重复数据上退化为 O(nm)
+	guard bool
+
+	// Two-Way 的临界分解位置和周期，periodic 表示模式以 period 为周期
+	ell, period int
+	periodic    bool
+
+	hash, pow uint32 // Rabin-Karp 的模式哈希和 primeRK^len(pattern)
+}
+
+// NewSearcher 为 pattern 创建使用 algo 算法的 Searcher，pattern 会被复制。
+// SearchAuto 在模式短于 64 字节时使用 bytes.Index，否则使用 Horspool；
+// 此时如果文本中末尾字节与模式相同的候选位置过多（如高度重复的数据），剩余部分改用 bytes.Index。
+func NewSearcher(pattern []byte, algo SearchAlgorithm) *Searcher {
+	s := newSearcher(bytes.Clone(pattern), algo)
+	return &s
+}
+
+// newSearcher 与 NewSearcher 相同，但不复制 pattern，调用方保证查找期间它不会被修改
+func newSearcher(pattern []byte, algo SearchAlgorithm) Searcher {
+	s := Searcher{pattern: pattern, algo: algo}
+	if algo == SearchAuto {
+		s.algo = SearchStdlib
+		if len(pattern) >= autoHorspoolLen {
+			s.algo, s.guard = SearchHorspool, true
+		}
+	}
+	switch s.algo {
+	case SearchStdlib:
+	case SearchHorspool:
+		horspoolTable(&s.skip, s.pattern)
+	case SearchTwoWay:
+		s.ell, s.period, s.periodic = criticalFactorization(s.pattern)
+	case SearchRabinKarp:
+		s.hash, s.pow = hashRK(s.pattern)
+	default:
+		panic("byte: unknown search algorithm")
+	}
+	return s
+}
+
+// Algorithm 返回实际使用的算法，SearchAuto 会被替换为选中的算法。
+func (s *Searcher) Algorithm() SearchAlgorithm {
+	return s.algo
+}
+
+// Index 返回模式在 text 中第一次出现的位置，没有时返回 -1。空模式匹配位置 0。
+func (s *Searcher) Index(text []byte) int {
+	m := len(s.pattern)
+	switch {
+	case m == 0:
+		return 0
+	case m > len(text):
+		return -1
+	}
+	switch s.algo {
+	case SearchHorspool:
+		return s.indexHorspool(text)
+	case SearchTwoWay:
+		return s.indexTwoWay(text)
+	case SearchRabinKarp:
+		return s.indexRabinKarp(text)
+	}
+	return bytes.Index(text, s.pattern)
+}
+
+// IndexAll 从左到右返回模式在 text 中所有不重叠出现的位置，与 bytes.Count 的计数方式相同。
+// 空模式匹配每个字节位置，包括 len(text)。
+func (s *Searcher) IndexAll(text []byte) []int {
+	var all []int
+	step := max(len(s.pattern), 1)
+	for from := 0; from <= len(text); {
+		i := s.Index(text[from:])
+		if i < 0 {
+			break
+		}
+		all = append(all, from+i)
+		from += i + step
+	}
+	return all
+}
+
+// horspoolTable 把窗口末尾字节为 c 时窗口可以右移的距离填入 skip
+func horspoolTable(skip *[256]uint8, p []byte) {
+	m := len(p)
+	for i := range skip {
+		skip[i] = uint8(min(m, 255))
+	}
+	for i := 0; i < m-1; i++ {
+		skip[p[i]] = uint8(min(m-1-i, 255))
+	}
+}
+
+func (s *Searcher) indexHorspool(text []byte) int {
+	p := s.pattern
+	m := len(p)
+	last := p[m-1]
+	candidates := 0
+	for i := 0; i <= len(text)-m; {
+		c := text[i+m-1]
+		if c == last {
+			if bytes.Equal(text[i:i+m-1], p[:m-1]) {
+				return i
+			}
+			// 平均每 16 字节超过一个候选位置时跳跃的收益已经很小
+			if candidates++; s.guard && candidates > 16+i>>4 {
+				if j := bytes.Index(text[i:], p); j >= 0 {
+					return i + j
+				}
+				return -1
+			}
+		}
+		i += int(s.skip[c])
+	}
+	return -1
+}
+
+// criticalFactorization 计算双向算法需要的临界分解：
+// 分别按正序和逆序比较取最大后缀，选择起点靠后的一个。
+// ell 是左半部分最后一个字节的下标（可以是 -1），period 是右半部分的周期，
+// periodic 表示整个模式也以 period 为周期。
+func criticalFactorization(p []byte) (ell, period int, periodic bool) {
+	if len(p) == 0 {
+		return -1, 1, false
+	}
+	i, pi := maxSuffix(p, false)
+	j, pj := maxSuffix(p, true)
+	ell, period = i, pi
+	if j > i {
+		ell, period = j, pj
+	}
+	if bytes.Equal(p[:ell+1], p[period:period+ell+1]) {
+		return ell, period, true
+	}
+	return ell, max(ell+1, len(p)-ell-1) + 1, false
+}
+
+// maxSuffix 返回 p 按字典序（reverse 为 true 时按逆序）最大的后缀的起点减一，以及该后缀的周期
+func maxSuffix(p []byte, reverse bool) (ms, period int) {
+	ms, j, k, period := -1, 0, 1, 1
+	for j+k < len(p) {
+		a, b := p[j+k], p[ms+k]
+		if reverse {
+			a, b = b, a
+		}
+		switch {
+		case a < b:
+			j += k
+			k = 1
+			period = j - ms
+		case a == b:
+			if k != period {
+				k++
+			} else {
+				j += period
+				k = 1
+			}
+		default:
+			ms = j
+			j = ms + 1
+			k, period = 1, 1
+		}
+	}
+	return ms, period
+}
+
+func (s *Searcher) indexTwoWay(text []byte) int {
+	p := s.pattern
+	m, ell, period := len(p), s.ell, s.period
+	if s.periodic {
+		// memory 之前的部分在上次移动后已知匹配，不用再比较
+		memory := -1
+		for j := 0; j <= len(text)-m; {
+			i := max(ell, memory) + 1
+			for i < m && p[i] == text[i+j] {
+				i++
+			}
+			if i < m {
+				j += i - ell
+				memory = -1
+				continue
+			}
+			i = ell
+			for i > memory && p[i] == text[i+j] {
+				i--
+			}
+			if i <= memory {
+				return j
+			}
+			j += period
+			memory = m - period - 1
+		}
+		return -1
+	}
+	for j := 0; j <= len(text)-m; {
+		i := ell + 1
+		for i < m && p[i] == text[i+j] {
+			i++
+		}
+		if i < m {
+			j += i - ell
+			continue
+		}
+		i = ell
+		for i >= 0 && p[i] == text[i+j] {
+			i--
+		}
+		if i < 0 {
+			return j
+		}
+		j += period
+	}
+	return -1
+}
+
+// primeRK 是 Rabin-Karp 使用的乘数，与标准库相同
+const primeRK = 16777619
+
+// hashRK 返回 p 的哈希值和 primeRK^len(p)，用于从窗口哈希中移除最早的字节
+func hashRK(p []byte) (hash, pow uint32) {
+	for _, c := range p {
+		hash = hash*primeRK + uint32(c)
+	}
+	pow = 1
+	for sq, i := uint32(primeRK), len(p); i > 0; i >>= 1 {
+		if i&1 != 0 {
+			pow *= sq
+		}
+		sq *= sq
+	}
+	return hash, pow
+}
+
+func (s *Searcher) indexRabinKarp(text []byte) int {
+	p := s.pattern
+	m := len(p)
+	var h uint32
+	for _, c := range text[:m] {
+		h = h*primeRK + uint32(c)
+	}
+	for i := 0; ; i++ {
+		if h == s.hash && bytes.Equal(text[i:i+m], p) {
+			return i
+		}
+		if i+m >= len(text) {
+			return -1
+		}
+		h = h*primeRK + uint32(text[i+m]) - s.pow*uint32(text[i])
+	}
+}
@@ -0,0 +1,176 @@
+package byte
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+var searchAlgorithms = []SearchAlgorithm{SearchAuto, SearchStdlib, SearchHorspool, SearchTwoWay, SearchRabinKarp}
+
+// naiveIndexAll 逐个位置比较，返回不重叠的匹配位置
+func naiveIndexAll(text, pattern []byte) []int {
+	var all []int
+	for i := 0; i+len(pattern) <= len(text); {
+		if bytes.Equal(text[i:i+len(pattern)], pattern) {
+			all = append(all, i)
+			i += max(len(pattern), 1)
+			continue
+		}
+		i++
+	}
+	return all
+}
+
+func checkSearch(t *testing.T, text, pattern []byte) {
+	t.Helper()
+	want := naiveIndexAll(text, pattern)
+	for _, algo := range searchAlgorithms {
+		s := NewSearcher(pattern, algo)
+		wantIndex := bytes.Index(text, pattern)
+		if got := s.Index(text); got != wantIndex {
+			t.Fatalf("%v: Index(%q, %q) = %d, want %d", algo, text, pattern, got, wantIndex)
+		}
+		if got := s.IndexAll(text); fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Fatalf("%v: IndexAll(%q, %q) = %v, want %v", algo, text, pattern, got, want)
+		}
+	}
+}
+
+func TestSearch(t *testing.T) {
+	tests := []struct{ text, pattern string }{
+		{"", ""},
+		{"abc", ""},
+		{"", "a"},
+		{"a", "a"},
+		{"ab", "abc"},
+		{"hello world", "world"},
+		{"hello world", "o"},
+		{"aaaaaaa", "aa"},
+		{"abababab", "abab"},
+		{"abaababaabaababaab", "abaababa"},
+		{"xxxxxxxxxxxxxxxxy", "xxxy"},
+		{"GCATCGCAGAGAGTATACAGTACG", "GCAGAGAG"},
+		{"zzzzzzzzzz", "zzzzzzzzzzz"},
+		{"\xff\x00\xff\x00\xff", "\x00\xff"},
+	}
+	for _, test := range tests {
+		checkSearch(t, []byte(test.text), []byte(test.pattern))
+	}
+}
+
+func TestSearchRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	// 小字母表容易产生周期性的模式和大量部分匹配
+	for _, alphabet := range []string{"ab", "abc", "acgt", "\x00\x01\xff"} {
+		for i := 0; i < 500; i++ {
+			gen := func(n int) []byte {
+				b := make([]byte, n)
+				for j := range b {
+					b[j] = alphabet[rng.Intn(len(alphabet))]
+				}
+				return b
+			}
+			text := gen(rng.Intn(200))
+			pattern := gen(1 + rng.Intn(12))
+			if rng.Intn(2) == 0 && len(text) > 0 {
+				start := rng.Intn(len(text))
+				pattern = text[start : start+rng.Intn(min(40, len(text)-start)+1)]
+			}
+			checkSearch(t, text, pattern)
+		}
+	}
+}
+
+func TestSearchAuto(t *testing.T) {
+	if got := NewSearcher([]byte("short"), SearchAuto).Algorithm(); got != SearchStdlib {
+		t.Errorf("short pattern algorithm = %v, want Stdlib", got)
+	}
+	long := bytes.Repeat([]byte("x"), autoHorspoolLen)
+	if got := NewSearcher(long, SearchAuto).Algorithm(); got != SearchHorspool {
+		t.Errorf("long pattern algorithm = %v, want Horspool", got)
+	}
+
+	// 重复数据上候选位置过多，改用 bytes.Index 后结果不变
+	pattern := []byte("b" + strings.Repeat("a", autoHorspoolLen-1))
+	for _, text := range [][]byte{
+		bytes.Repeat([]byte("a"), 5000),
+		append(bytes.Repeat([]byte("a"), 5000), pattern...),
+		append(append(bytes.Repeat([]byte("a"), 3000), pattern...), bytes.Repeat([]byte("a"), 3000)...),
+	} {
+		checkSearch(t, text, pattern)
+	}
+}
+
+func TestSearcherCopiesPattern(t *testing.T) {
+	pattern := []byte("needle")
+	s := NewSearcher(pattern, SearchHorspool)
+	pattern[0] = 'N'
+	if got := s.Index([]byte("a needle")); got != 2 {
+		t.Errorf("Index = %d, want 2", got)
+	}
+}
+
+// searchLogData 生成类似服务访问日志的文本
+func searchLogData(lines int) []byte {
+	rng := rand.New(rand.NewSource(1))
+	levels := []string{"INFO ", "INFO ", "INFO ", "DEBUG", "WARN ", "ERROR"}
+	paths := []string{"/api/v1/users", "/api/v1/orders", "/healthz", "/api/v2/search", "/static/app.js"}
+	var sb strings.Builder
+	for i := 0; i < lines; i++ {
+		fmt.Fprintf(&sb, "2024-05-%02dT%02d:%02d:%02d.%03dZ %s [http] %s %s/%d status=%d latency=%dms request_id=%016x\n",
+			1+i/86400%28, i/3600%24, i/60%60, i%60, rng.Intn(1000), levels[rng.Intn(len(levels))],
+			[]string{"GET", "POST", "PUT"}[rng.Intn(3)], paths[rng.Intn(len(paths))], rng.Intn(10000),
+			[]int{200, 200, 200, 201, 404, 500}[rng.Intn(6)], rng.Intn(500), rng.Uint64())
+	}
+	return []byte(sb.String())
+}
+
+// BenchmarkSearch 在约 1MB 的日志中查找不同长度的模式，用于确定 SearchAuto 的选择。
+// bytes.Index 的速度取决于模式首字节在文本中是否常见，所以同一长度分别测试两种模式。
+func BenchmarkSearch(b *testing.B) {
+	logs := searchLogData(8000)
+	tail := strings.Repeat("0123456789abcdefXYZ", 10)
+	cases := []struct {
+		name          string
+		data, pattern []byte
+	}{
+		{"len4", logs, []byte("WARN")},
+		{"len16", logs, []byte("/api/v2/search/9")},
+		{"len32/CommonFirstByte", logs, []byte(("request_id=" + tail)[:32])},
+		{"len32/RareFirstByte", logs, []byte(("Zq" + tail)[:32])},
+		{"len64/CommonFirstByte", logs, []byte(("request_id=" + tail)[:64])},
+		{"len64/RareFirstByte", logs, []byte(("Zq" + tail)[:64])},
+		{"len128/CommonFirstByte", logs, []byte(("request_id=" + tail)[:128])},
+		// Horspool 在这种数据上退化为 O(nm)，Auto 会改用 bytes.Index，Two-Way 和 Rabin-Karp 不受影响
+		{"Repetitive", bytes.Repeat([]byte("a"), 1<<20), []byte("b" + strings.Repeat("a", 63))},
+	}
+	for _, c := range cases {
+		b.Run(c.name+"/BytesIndex", func(b *testing.B) {
+			b.SetBytes(int64(len(c.data)))
+			for i := 0; i < b.N; i++ {
+				for rest := c.data; ; {
+					j := bytes.Index(rest, c.pattern)
+					if j < 0 {
+						break
+					}
+					rest = rest[j+len(c.pattern):]
+				}
+			}
+		})
+		for _, algo := range searchAlgorithms {
+			if algo == SearchStdlib || algo == SearchAuto && len(c.pattern) < autoHorspoolLen {
+				continue
+			}
+			s := NewSearcher(c.pattern, algo)
+			b.Run(c.name+"/"+algo.String(), func(b *testing.B) {
+				b.SetBytes(int64(len(c.data)))
+				for i := 0; i < b.N; i++ {
+					s.IndexAll(c.data)
+				}
+			})
+		}
+	}
+}
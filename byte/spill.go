@@ -0,0 +1,185 @@
+package byte
+
+import (
+	"errors"
+	"io"
+	"os"
+)
+
+// ErrTooLarge 表示写入后 SpillBuffer 的总大小会超过 MaxSize
+var ErrTooLarge = errors.New("byte: spill buffer exceeds max size")
+
+// DefaultSpillThreshold 是 SpillOptions.Threshold 为 0 时内存中最多保存的字节数
+const DefaultSpillThreshold = 1 << 20
+
+// SpillOptions 是创建 SpillBuffer 的选项
+type SpillOptions struct {
+	// Threshold 是内存中最多保存的字节数，超过后全部数据转存到临时文件。
+	// 0 表示 DefaultSpillThreshold，负数表示总是使用临时文件
+	Threshold int64
+	// MaxSize 是总大小的上限，超过时 Write 返回 ErrTooLarge，0 表示不限制
+	MaxSize int64
+	// Dir 是临时文件所在的目录，空表示 os.TempDir()
+	Dir string
+}
+
+// SpillBuffer 先在内存中缓存数据，总大小超过阈值后透明地转存到临时文件，
+// 适合缓存大小未知、偶尔很大的数据（如 HTTP 请求体），避免占用过多内存。
+// 写入总是追加到末尾，读取位置独立，可以用 Seek 移动，因此写完后可以反复读取。
+// 实现了 io.Writer、io.Reader、io.Seeker、io.WriterTo 和 io.Closer。
+// 使用完必须调用 Close 删除临时文件。SpillBuffer 不能被多个 goroutine 同时使用。
+type SpillBuffer struct {
+	opts   SpillOptions
+	mem    []byte
+	file   *os.File // 转存后的临时文件，此后 mem 不再使用
+	size   int64
+	off    int64 // 读取位置
+	closed bool
+}
+
+// NewSpillBuffer 创建空的 SpillBuffer，直到需要转存时才创建临时文件。
+func NewSpillBuffer(opts SpillOptions) *SpillBuffer {
+	if opts.Threshold == 0 {
+		opts.Threshold = DefaultSpillThreshold
+	}
+	return &SpillBuffer{opts: opts}
+}
+
+// Write 把 p 追加到末尾。写入后总大小会超过 MaxSize 时不写入任何数据，返回 ErrTooLarge。
+func (b *SpillBuffer) Write(p []byte) (int, error) {
+	if b.closed {
+		return 0, os.ErrClosed
+	}
+	if b.opts.MaxSize > 0 && int64(len(p)) > b.opts.MaxSize-b.size {
+		return 0, ErrTooLarge
+	}
+	if b.file == nil && b.size+int64(len(p)) > b.opts.Threshold {
+		if err := b.spill(); err != nil {
+			return 0, err
+		}
+	}
+	if b.file == nil {
+		b.mem = append(b.mem, p...)
+		b.size += int64(len(p))
+		return len(p), nil
+	}
+	n, err := b.file.WriteAt(p, b.size)
+	b.size += int64(n)
+	return n, err
+}
+
+// spill 创建临时文件并写入内存中的数据
+func (b *SpillBuffer) spill() error {
+	f, err := os.CreateTemp(b.opts.Dir, "spillbuffer-*")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(b.mem); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	b.file, b.mem = f, nil
+	return nil
+}
+
+// Read 实现 io.Reader，从读取位置开始读取。
+func (b *SpillBuffer) Read(p []byte) (int, error) {
+	if b.closed {
+		return 0, os.ErrClosed
+	}
+	if b.off >= b.size {
+		if len(p) == 0 {
+			return 0, nil
+		}
+		return 0, io.EOF
+	}
+	p = p[:min(int64(len(p)), b.size-b.off)]
+	var n int
+	var err error
+	if b.file == nil {
+		n = copy(p, b.mem[b.off:])
+	} else {
+		n, err = b.file.ReadAt(p, b.off)
+		if err == io.EOF && n == len(p) {
+			err = nil
+		}
+	}
+	b.off += int64(n)
+	return n, err
+}
+
+// Seek 实现 io.Seeker，设置下一次 Read 或 WriteTo 的位置，不影响写入。
+// 位置可以超过末尾，此时读取返回 io.EOF。
+func (b *SpillBuffer) Seek(offset int64, whence int) (int64, error) {
+	if b.closed {
+		return 0, os.ErrClosed
+	}
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += b.off
+	case io.SeekEnd:
+		offset += b.size
+	default:
+		return 0, errors.New("byte: SpillBuffer.Seek: invalid whence")
+	}
+	if offset < 0 {
+		return 0, errors.New("byte: SpillBuffer.Seek: negative position")
+	}
+	b.off = offset
+	return offset, nil
+}
+
+// WriteTo 实现 io.WriterTo，把读取位置之后的全部数据写到 w。
+func (b *SpillBuffer) WriteTo(w io.Writer) (int64, error) {
+	if b.closed {
+		return 0, os.ErrClosed
+	}
+	if b.off >= b.size {
+		return 0, nil
+	}
+	var n int64
+	var err error
+	if b.file == nil {
+		var k int
+		k, err = w.Write(b.mem[b.off:])
+		if err == nil && int64(k) < b.size-b.off {
+			err = io.ErrShortWrite
+		}
+		n = int64(k)
+	} else {
+		n, err = io.Copy(w, io.NewSectionReader(b.file, b.off, b.size-b.off))
+	}
+	b.off += n
+	return n, err
+}
+
+// Size 返回写入的总字节数。
+func (b *SpillBuffer) Size() int64 {
+	return b.size
+}
+
+// Spilled 判断数据是否已经转存到临时文件。
+func (b *SpillBuffer) Spilled() bool {
+	return b.file != nil
+}
+
+// Close 释放内存并删除临时文件，之后的所有操作返回 os.ErrClosed。重复调用 Close 返回 nil。
+func (b *SpillBuffer) Close() error {
+	if b.closed {
+		return nil
+	}
+	b.closed = true
+	b.mem = nil
+	if b.file == nil {
+		return nil
+	}
+	name := b.file.Name()
+	err := b.file.Close()
+	b.file = nil
+	if rmErr := os.Remove(name); err == nil {
+		err = rmErr
+	}
+	return err
+}
@@ -0,0 +1,196 @@
+package byte
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math/rand"
+	"os"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// spillFiles 返回 dir 中的临时文件数量
+func spillFiles(t *testing.T, dir string) int {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return len(entries)
+}
+
+func TestSpillBuffer(t *testing.T) {
+	data := make([]byte, 10000)
+	rand.New(rand.NewSource(1)).Read(data)
+
+	for _, threshold := range []int64{-1, 100, 1 << 20} {
+		dir := t.TempDir()
+		b := NewSpillBuffer(SpillOptions{Threshold: threshold, Dir: dir})
+		for rest := data; len(rest) > 0; {
+			k := min(len(rest), 333)
+			if n, err := b.Write(rest[:k]); n != k || err != nil {
+				t.Fatalf("Write = %d, %v", n, err)
+			}
+			rest = rest[k:]
+		}
+		wantSpilled, wantFiles := threshold < int64(len(data)), 0
+		if wantSpilled {
+			wantFiles = 1
+		}
+		if b.Spilled() != wantSpilled || spillFiles(t, dir) != wantFiles {
+			t.Errorf("threshold %d: Spilled() = %v with %d files", threshold, b.Spilled(), spillFiles(t, dir))
+		}
+		if b.Size() != int64(len(data)) {
+			t.Errorf("threshold %d: Size() = %d", threshold, b.Size())
+		}
+		// iotest.TestReader 同时检查 io.Seeker 的行为
+		if err := iotest.TestReader(b, data); err != nil {
+			t.Errorf("threshold %d: %v", threshold, err)
+		}
+
+		if _, err := b.Seek(-100, io.SeekEnd); err != nil {
+			t.Fatal(err)
+		}
+		var out bytes.Buffer
+		if n, err := b.WriteTo(&out); n != 100 || err != nil || !bytes.Equal(out.Bytes(), data[len(data)-100:]) {
+			t.Errorf("threshold %d: WriteTo after Seek = %d, %v", threshold, n, err)
+		}
+		if n, err := b.Read(make([]byte, 1)); n != 0 || err != io.EOF {
+			t.Errorf("threshold %d: Read at end = %d, %v; want io.EOF", threshold, n, err)
+		}
+
+		if err := b.Close(); err != nil {
+			t.Errorf("threshold %d: Close() = %v", threshold, err)
+		}
+		if n := spillFiles(t, dir); n != 0 {
+			t.Errorf("threshold %d: %d temp files left after Close", threshold, n)
+		}
+	}
+}
+
+func TestSpillBufferWriteAfterRead(t *testing.T) {
+	b := NewSpillBuffer(SpillOptions{Threshold: 8, Dir: t.TempDir()})
+	defer b.Close()
+	b.Write([]byte("hello"))
+	p := make([]byte, 3)
+	b.Read(p)
+	// 写入追加到末尾，不影响读取位置；这次写入会触发转存
+	b.Write([]byte(" world"))
+	rest, err := io.ReadAll(b)
+	if string(rest) != "lo world" || err != nil || !b.Spilled() {
+		t.Errorf("ReadAll = %q, %v; Spilled() = %v", rest, err, b.Spilled())
+	}
+}
+
+func TestSpillBufferMaxSize(t *testing.T) {
+	b := NewSpillBuffer(SpillOptions{Threshold: 4, MaxSize: 10, Dir: t.TempDir()})
+	defer b.Close()
+	if _, err := io.Copy(b, strings.NewReader("0123456789")); err != nil {
+		t.Fatalf("Copy within MaxSize: %v", err)
+	}
+	if n, err := b.Write([]byte("x")); n != 0 || !errors.Is(err, ErrTooLarge) {
+		t.Errorf("Write over MaxSize = %d, %v; want ErrTooLarge", n, err)
+	}
+	if b.Size() != 10 {
+		t.Errorf("Size() = %d after rejected write, want 10", b.Size())
+	}
+
+	// 超过上限的写入不会写入任何数据
+	b2 := NewSpillBuffer(SpillOptions{MaxSize: 5})
+	defer b2.Close()
+	if _, err := io.Copy(b2, strings.NewReader("too large body")); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("Copy over MaxSize error = %v, want ErrTooLarge", err)
+	}
+	if b2.Size() != 0 {
+		t.Errorf("Size() = %d, want 0", b2.Size())
+	}
+}
+
+func TestSpillBufferSeek(t *testing.T) {
+	b := NewSpillBuffer(SpillOptions{})
+	defer b.Close()
+	b.Write([]byte("0123456789"))
+	for _, test := range []struct {
+		offset int64
+		whence int
+		want   int64
+	}{
+		{3, io.SeekStart, 3},
+		{2, io.SeekCurrent, 5},
+		{-1, io.SeekEnd, 9},
+		{20, io.SeekStart, 20},
+	} {
+		if got, err := b.Seek(test.offset, test.whence); got != test.want || err != nil {
+			t.Errorf("Seek(%d, %d) = %d, %v; want %d", test.offset, test.whence, got, err, test.want)
+		}
+	}
+	if n, err := b.Read(make([]byte, 4)); n != 0 || err != io.EOF {
+		t.Errorf("Read past end = %d, %v; want io.EOF", n, err)
+	}
+	if _, err := b.Seek(-1, io.SeekStart); err == nil {
+		t.Error("Seek to negative position succeeded")
+	}
+	if _, err := b.Seek(0, 42); err == nil {
+		t.Error("Seek with invalid whence succeeded")
+	}
+}
+
+func TestSpillBufferClose(t *testing.T) {
+	b := NewSpillBuffer(SpillOptions{Threshold: 1, Dir: t.TempDir()})
+	b.Write([]byte("spilled"))
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Close(); err != nil {
+		t.Errorf("second Close() = %v", err)
+	}
+	if _, err := b.Write([]byte("x")); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("Write after Close = %v, want os.ErrClosed", err)
+	}
+	if _, err := b.Read(make([]byte, 1)); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("Read after Close = %v, want os.ErrClosed", err)
+	}
+	if _, err := b.Seek(0, io.SeekStart); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("Seek after Close = %v, want os.ErrClosed", err)
+	}
+}
+
+func TestSpillBufferTempDirError(t *testing.T) {
+	dir := t.TempDir() + "/missing"
+	b := NewSpillBuffer(SpillOptions{Threshold: 2, Dir: dir})
+	defer b.Close()
+	b.Write([]byte("ab"))
+	if n, err := b.Write([]byte("c")); n != 0 || err == nil {
+		t.Errorf("Write with unusable Dir = %d, %v; want error", n, err)
+	}
+	// 转存失败时内存中的数据保持不变
+	if got, _ := io.ReadAll(b); string(got) != "ab" {
+		t.Errorf("ReadAll = %q, want %q", got, "ab")
+	}
+}
+
+func BenchmarkSpillBuffer(b *testing.B) {
+	chunk := make([]byte, 32<<10)
+	for _, c := range []struct {
+		name string
+		size int
+	}{
+		{"Memory256K", 256 << 10},
+		{"Spilled4M", 4 << 20},
+	} {
+		b.Run(c.name, func(b *testing.B) {
+			dir := b.TempDir()
+			b.SetBytes(int64(c.size))
+			for i := 0; i < b.N; i++ {
+				sb := NewSpillBuffer(SpillOptions{Dir: dir})
+				for written := 0; written < c.size; written += len(chunk) {
+					sb.Write(chunk)
+				}
+				sb.WriteTo(io.Discard)
+				sb.Close()
+			}
+		})
+	}
+}
@@ -0,0 +1,334 @@
+#include "checksum.h"
+#include <pthread.h>
+#include <string.h>
+
+/* ---------------- CRC32 ---------------- */
+
+static uint32_t crc32_table_ieee[256];
+static uint32_t crc32_table_castagnoli[256];
+static pthread_once_t crc32_tables_once = PTHREAD_ONCE_INIT;
+
+static void crc32_fill_table(uint32_t* table, uint32_t poly) {
+    for (uint32_t i = 0; i < 256; i++) {
+        uint32_t crc = i;
+        for (int j = 0; j < 8; j++) {
+            crc = (crc & 1) ? (crc >> 1) ^ poly : crc >> 1;
+        }
+        table[i] = crc;
+    }
+}
+
+static void crc32_build_tables(void) {
+    crc32_fill_table(crc32_table_ieee, CRC32_POLY_IEEE);
+    crc32_fill_table(crc32_table_castagnoli, CRC32_POLY_CASTAGNOLI);
+}
+
+/**
+ * @brief 初始化 CRC32 上下文。
+ *
+ * IEEE 和 Castagnoli 复制预先生成的查找表，其他多项式在这里生成查找表。
+ *
+ * @param ctx 上下文。
+ * @param poly 反射形式的多项式，例如 CRC32_POLY_IEEE、CRC32_POLY_CASTAGNOLI。
+ */
+void crc32_init(crc32_ctx* ctx, uint32_t poly) {
+    pthread_once(&crc32_tables_once, crc32_build_tables);
+    if (poly == CRC32_POLY_IEEE) {
+        memcpy(ctx->table, crc32_table_ieee, sizeof(ctx->table));
+    } else if (poly == CRC32_POLY_CASTAGNOLI) {
+        memcpy(ctx->table, crc32_table_castagnoli, sizeof(ctx->table));
+    } else if (ctx->poly != poly) {
+        crc32_fill_table(ctx->table, poly);
+    }
+    ctx->poly = poly;
+    ctx->crc = 0xFFFFFFFFu;
+}
+
+/**
+ * @brief 向 CRC32 上下文追加数据。
+ *
+ * @param ctx 上下文。
+ * @param data 数据。
+ * @param len 数据长度。
+ */
+void crc32_update(crc32_ctx* ctx, const uint8_t* data, size_t len) {
+    const uint32_t* table = ctx->table;
+    uint32_t crc = ctx->crc;
+    for (size_t i = 0; i < len; i++) {
+        crc = table[(crc ^ data[i]) & 0xFF] ^ (crc >> 8);
+    }
+    ctx->crc = crc;
+}
+
+/**
+ * @brief 返回当前 CRC32 校验值，不修改上下文。
+ *
+ * @param ctx 上下文。
+ * @return 校验值。
+ */
+uint32_t crc32_final(const crc32_ctx* ctx) {
+    return ctx->crc ^ 0xFFFFFFFFu;
+}
+
+/* ---------------- Adler-32 ---------------- */
+
+#define ADLER32_MOD 65521u
+// 保证 b 在取模前不会溢出 32 位的最大块长度
+#define ADLER32_NMAX 5552
+
+/**
+ * @brief 初始化 Adler-32 上下文。
+ *
+ * @param ctx 上下文。
+ */
+void adler32_init(adler32_ctx* ctx) {
+    ctx->a = 1;
+    ctx->b = 0;
+}
+
+/**
+ * @brief 向 Adler-32 上下文追加数据。
+ *
+ * @param ctx 上下文。
+ * @param data 数据。
+ * @param len 数据长度。
+ */
+void adler32_update(adler32_ctx* ctx, const uint8_t* data, size_t len) {
+    uint32_t a = ctx->a;
+    uint32_t b = ctx->b;
+    while (len > 0) {
+        size_t n = len < ADLER32_NMAX ? len : ADLER32_NMAX;
+        len -= n;
+        while (n-- > 0) {
+            a += *data++;
+            b += a;
+        }
+        a %= ADLER32_MOD;
+        b %= ADLER32_MOD;
+    }
+    ctx->a = a;
+    ctx->b = b;
+}
+
+/**
+ * @brief 返回当前 Adler-32 校验值，不修改上下文。
+ *
+ * @param ctx 上下文。
+ * @return 校验值。
+ */
+uint32_t adler32_final(const adler32_ctx* ctx) {
+    return (ctx->b << 16) | ctx->a;
+}
+
+/* ---------------- FNV-1a ---------------- */
+
+#define FNV32_OFFSET 2166136261u
+#define FNV32_PRIME  16777619u
+#define FNV64_OFFSET 14695981039346656037ull
+#define FNV64_PRIME  1099511628211ull
+
+/**
+ * @brief 初始化 32 位 FNV-1a 上下文。
+ *
+ * @param ctx 上下文。
+ */
+void fnv1a32_init(fnv1a32_ctx* ctx) {
+    ctx->h = FNV32_OFFSET;
+}
+
+/**
+ * @brief 向 32 位 FNV-1a 上下文追加数据，每个字节先异或再乘以 FNV 质数。
+ *
+ * @param ctx 上下文。
+ * @param data 数据。
+ * @param len 数据长度。
+ */
+void fnv1a32_update(fnv1a32_ctx* ctx, const uint8_t* data, size_t len) {
+    uint32_t h = ctx->h;
+    for (size_t i = 0; i < len; i++) {
+        h ^= data[i];
+        h *= FNV32_PRIME;
+    }
+    ctx->h = h;
+}
+
+/**
+ * @brief 返回当前 32 位 FNV-1a 哈希值，不修改上下文。
+ *
+ * @param ctx 上下文。
+ * @return 哈希值。
+ */
+uint32_t fnv1a32_final(const fnv1a32_ctx* ctx) {
+    return ctx->h;
+}
+
+/**
+ * @brief 初始化 64 位 FNV-1a 上下文。
+ *
+ * @param ctx 上下文。
+ */
+void fnv1a64_init(fnv1a64_ctx* ctx) {
+    ctx->h = FNV64_OFFSET;
+}
+
+/**
+ * @brief 向 64 位 FNV-1a 上下文追加数据，每个字节先异或再乘以 FNV 质数。
+ *
+ * @param ctx 上下文。
+ * @param data 数据。
+ * @param len 数据长度。
+ */
+void fnv1a64_update(fnv1a64_ctx* ctx, const uint8_t* data, size_t len) {
+    uint64_t h = ctx->h;
+    for (size_t i = 0; i < len; i++) {
+        h ^= data[i];
+        h *= FNV64_PRIME;
+    }
+    ctx->h = h;
+}
+
+/**
+ * @brief 返回当前 64 位 FNV-1a 哈希值，不修改上下文。
+ *
+ * @param ctx 上下文。
+ * @return 哈希值。
+ */
+uint64_t fnv1a64_final(const fnv1a64_ctx* ctx) {
+    return ctx->h;
+}
+
+/* ---------------- xxHash64 ---------------- */
+
+#define XXH_P1 11400714785074694791ull
+#define XXH_P2 14029467366897019727ull
+#define XXH_P3 1609587929392839161ull
+#define XXH_P4 9650029242287828579ull
+#define XXH_P5 2870177450012600261ull
+
+static inline uint64_t xxh_rotl(uint64_t x, int r) {
+    return (x << r) | (x >> (64 - r));
+}
+
+// 按小端序读取，避免依赖主机字节序和对齐
+static inline uint64_t xxh_read64(const uint8_t* p) {
+    return (uint64_t)p[0] | (uint64_t)p[1] << 8 | (uint64_t)p[2] << 16 |
+           (uint64_t)p[3] << 24 | (uint64_t)p[4] << 32 | (uint64_t)p[5] << 40 |
+           (uint64_t)p[6] << 48 | (uint64_t)p[7] << 56;
+}
+
+static inline uint32_t xxh_read32(const uint8_t* p) {
+    return (uint32_t)p[0] | (uint32_t)p[1] << 8 | (uint32_t)p[2] << 16 |
+           (uint32_t)p[3] << 24;
+}
+
+static inline uint64_t xxh_round(uint64_t acc, uint64_t input) {
+    acc += input * XXH_P2;
+    acc = xxh_rotl(acc, 31);
+    return acc * XXH_P1;
+}
+
+static inline uint64_t xxh_merge_round(uint64_t acc, uint64_t val) {
+    acc ^= xxh_round(0, val);
+    return acc * XXH_P1 + XXH_P4;
+}
+
+/**
+ * @brief 初始化 xxHash64 上下文。
+ *
+ * @param ctx 上下文。
+ * @param seed 种子。
+ */
+void xxh64_init(xxh64_ctx* ctx, uint64_t seed) {
+    memset(ctx, 0, sizeof(*ctx));
+    ctx->seed = seed;
+    ctx->v[0] = seed + XXH_P1 + XXH_P2;
+    ctx->v[1] = seed + XXH_P2;
+    ctx->v[2] = seed;
+    ctx->v[3] = seed - XXH_P1;
+}
+
+/**
+ * @brief 向 xxHash64 上下文追加数据，不足 32 字节的部分暂存在 mem 中。
+ *
+ * @param ctx 上下文。
+ * @param data 数据。
+ * @param len 数据长度。
+ */
+void xxh64_update(xxh64_ctx* ctx, const uint8_t* data, size_t len) {
+    const uint8_t* p = data;
+    const uint8_t* end = data + len;
+    ctx->total_len += len;
+
+    if (ctx->mem_size + len < 32) {
+        memcpy(ctx->mem + ctx->mem_size, p, len);
+        ctx->mem_size += (uint32_t)len;
+        return;
+    }
+
+    if (ctx->mem_size > 0) {
+        size_t fill = 32 - ctx->mem_size;
+        memcpy(ctx->mem + ctx->mem_size, p, fill);
+        for (int i = 0; i < 4; i++) {
+            ctx->v[i] = xxh_round(ctx->v[i], xxh_read64(ctx->mem + i * 8));
+        }
+        p += fill;
+        ctx->mem_size = 0;
+    }
+
+    while (end - p >= 32) {
+        for (int i = 0; i < 4; i++) {
+            ctx->v[i] = xxh_round(ctx->v[i], xxh_read64(p + i * 8));
+        }
+        p += 32;
+    }
+
+    if (p < end) {
+        memcpy(ctx->mem, p, (size_t)(end - p));
+        ctx->mem_size = (uint32_t)(end - p);
+    }
+}
+
+/**
+ * @brief 返回当前 xxHash64 摘要，不修改上下文。
+ *
+ * @param ctx 上下文。
+ * @return 64 位摘要。
+ */
+uint64_t xxh64_final(const xxh64_ctx* ctx) {
+    uint64_t h;
+    if (ctx->total_len >= 32) {
+        h = xxh_rotl(ctx->v[0], 1) + xxh_rotl(ctx->v[1], 7) +
+            xxh_rotl(ctx->v[2], 12) + xxh_rotl(ctx->v[3], 18);
+        for (int i = 0; i < 4; i++) {
+            h = xxh_merge_round(h, ctx->v[i]);
+        }
+    } else {
+        h = ctx->seed + XXH_P5;
+    }
+    h += ctx->total_len;
+
+    const uint8_t* p = ctx->mem;
+    const uint8_t* end = ctx->mem + ctx->mem_size;
+    while (end - p >= 8) {
+        h ^= xxh_round(0, xxh_read64(p));
+        h = xxh_rotl(h, 27) * XXH_P1 + XXH_P4;
+        p += 8;
+    }
+    if (end - p >= 4) {
+        h ^= (uint64_t)xxh_read32(p) * XXH_P1;
+        h = xxh_rotl(h, 23) * XXH_P2 + XXH_P3;
+        p += 4;
+    }
+    while (p < end) {
+        h ^= (uint64_t)(*p) * XXH_P5;
+        h = xxh_rotl(h, 11) * XXH_P1;
+        p++;
+    }
+
+    h ^= h >> 33;
+    h *= XXH_P2;
+    h ^= h >> 29;
+    h *= XXH_P3;
+    h ^= h >> 32;
+    return h;
+}
@@ -0,0 +1,206 @@
+package cgo
+
+/*
+#include "checksum.h"
+*/
+import "C"
+import (
+	"hash"
+	"unsafe"
+)
+
+// 编译期检查各类型实现了标准库的 hash 接口
+var (
+	_ hash.Hash32 = (*CRC32)(nil)
+	_ hash.Hash32 = (*Adler32)(nil)
+	_ hash.Hash32 = (*FNV1a32)(nil)
+	_ hash.Hash64 = (*FNV1a64)(nil)
+	_ hash.Hash64 = (*XXHash64)(nil)
+)
+
+// CRC32 多项式，与 hash/crc32 中的 IEEE、Castagnoli 对应。
+const (
+	CRC32IEEEPoly       uint32 = C.CRC32_POLY_IEEE
+	CRC32CastagnoliPoly uint32 = C.CRC32_POLY_CASTAGNOLI
+)
+
+// cBytes 返回切片首字节的 C 指针和长度，空切片返回 nil。
+func cBytes(p []byte) (*C.uint8_t, C.size_t) {
+	if len(p) == 0 {
+		return nil, 0
+	}
+	return (*C.uint8_t)(unsafe.Pointer(&p[0])), C.size_t(len(p))
+}
+
+// appendUint32 按大端序追加，与标准库 Sum 的输出格式保持一致。
+func appendUint32(b []byte, v uint32) []byte {
+	return append(b, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
+
+func appendUint64(b []byte, v uint64) []byte {
+	return appendUint32(appendUint32(b, uint32(v>>32)), uint32(v))
+}
+
+// CRC32 是基于 C 实现的流式 CRC32 校验。
+type CRC32 struct {
+	ctx  C.crc32_ctx
+	poly uint32
+}
+
+// NewCRC32 创建使用指定多项式的 CRC32 校验，poly 是反射形式，与 hash/crc32 的 MakeTable 参数相同。
+// CRC32IEEEPoly 和 CRC32CastagnoliPoly 使用预先生成的查找表，其他多项式（如 crc32.Koopman）创建时生成。
+func NewCRC32(poly uint32) *CRC32 {
+	h := &CRC32{poly: poly}
+	h.Reset()
+	return h
+}
+
+// NewCRC32IEEE 创建 IEEE 多项式的 CRC32 校验。
+func NewCRC32IEEE() *CRC32 { return NewCRC32(CRC32IEEEPoly) }
+
+// NewCRC32Castagnoli 创建 Castagnoli 多项式的 CRC32 校验。
+func NewCRC32Castagnoli() *CRC32 { return NewCRC32(CRC32CastagnoliPoly) }
+
+func (h *CRC32) Write(p []byte) (int, error) {
+	data, n := cBytes(p)
+	C.crc32_update(&h.ctx, data, n)
+	return len(p), nil
+}
+
+func (h *CRC32) Sum32() uint32       { return uint32(C.crc32_final(&h.ctx)) }
+func (h *CRC32) Sum(b []byte) []byte { return appendUint32(b, h.Sum32()) }
+func (h *CRC32) Reset()              { C.crc32_init(&h.ctx, C.uint32_t(h.poly)) }
+func (h *CRC32) Size() int           { return 4 }
+func (h *CRC32) BlockSize() int      { return 1 }
+
+// Adler32 是基于 C 实现的流式 Adler-32 校验。
+type Adler32 struct {
+	ctx C.adler32_ctx
+}
+
+// NewAdler32 创建 Adler-32 校验。
+func NewAdler32() *Adler32 {
+	h := &Adler32{}
+	h.Reset()
+	return h
+}
+
+func (h *Adler32) Write(p []byte) (int, error) {
+	data, n := cBytes(p)
+	C.adler32_update(&h.ctx, data, n)
+	return len(p), nil
+}
+
+func (h *Adler32) Sum32() uint32       { return uint32(C.adler32_final(&h.ctx)) }
+func (h *Adler32) Sum(b []byte) []byte { return appendUint32(b, h.Sum32()) }
+func (h *Adler32) Reset()              { C.adler32_init(&h.ctx) }
+func (h *Adler32) Size() int           { return 4 }
+func (h *Adler32) BlockSize() int      { return 4 }
+
+// FNV1a32 是基于 C 实现的 32 位 FNV-1a 哈希。
+type FNV1a32 struct {
+	ctx C.fnv1a32_ctx
+}
+
+// NewFNV1a32 创建 32 位 FNV-1a 哈希。
+func NewFNV1a32() *FNV1a32 {
+	h := &FNV1a32{}
+	h.Reset()
+	return h
+}
+
+func (h *FNV1a32) Write(p []byte) (int, error) {
+	data, n := cBytes(p)
+	C.fnv1a32_update(&h.ctx, data, n)
+	return len(p), nil
+}
+
+func (h *FNV1a32) Sum32() uint32       { return uint32(C.fnv1a32_final(&h.ctx)) }
+func (h *FNV1a32) Sum(b []byte) []byte { return appendUint32(b, h.Sum32()) }
+func (h *FNV1a32) Reset()              { C.fnv1a32_init(&h.ctx) }
+func (h *FNV1a32) Size() int           { return 4 }
+func (h *FNV1a32) BlockSize() int      { return 1 }
+
+// FNV1a64 是基于 C 实现的 64 位 FNV-1a 哈希。
+type FNV1a64 struct {
+	ctx C.fnv1a64_ctx
+}
+
+// NewFNV1a64 创建 64 位 FNV-1a 哈希。
+func NewFNV1a64() *FNV1a64 {
+	h := &FNV1a64{}
+	h.Reset()
+	return h
+}
+
+func (h *FNV1a64) Write(p []byte) (int, error) {
+	data, n := cBytes(p)
+	C.fnv1a64_update(&h.ctx, data, n)
+	return len(p), nil
+}
+
+func (h *FNV1a64) Sum64() uint64       { return uint64(C.fnv1a64_final(&h.ctx)) }
+func (h *FNV1a64) Sum(b []byte) []byte { return appendUint64(b, h.Sum64()) }
+func (h *FNV1a64) Reset()              { C.fnv1a64_init(&h.ctx) }
+func (h *FNV1a64) Size() int           { return 8 }
+func (h *FNV1a64) BlockSize() int      { return 1 }
+
+// XXHash64 是基于 C 实现的流式 xxHash64 哈希。
+type XXHash64 struct {
+	ctx  C.xxh64_ctx
+	seed uint64
+}
+
+// NewXXHash64 创建使用指定种子的 xxHash64 哈希。
+func NewXXHash64(seed uint64) *XXHash64 {
+	h := &XXHash64{seed: seed}
+	h.Reset()
+	return h
+}
+
+func (h *XXHash64) Write(p []byte) (int, error) {
+	data, n := cBytes(p)
+	C.xxh64_update(&h.ctx, data, n)
+	return len(p), nil
+}
+
+func (h *XXHash64) Sum64() uint64       { return uint64(C.xxh64_final(&h.ctx)) }
+func (h *XXHash64) Sum(b []byte) []byte { return appendUint64(b, h.Sum64()) }
+func (h *XXHash64) Reset()              { C.xxh64_init(&h.ctx, C.uint64_t(h.seed)) }
+func (h *XXHash64) Size() int           { return 8 }
+func (h *XXHash64) BlockSize() int      { return 32 }
+
+// ChecksumCRC32 一次性计算 data 的 CRC32 校验值，poly 的含义与 NewCRC32 相同。
+func ChecksumCRC32(data []byte, poly uint32) uint32 {
+	h := NewCRC32(poly)
+	h.Write(data)
+	return h.Sum32()
+}
+
+// ChecksumAdler32 一次性计算 data 的 Adler-32 校验值。
+func ChecksumAdler32(data []byte) uint32 {
+	h := NewAdler32()
+	h.Write(data)
+	return h.Sum32()
+}
+
+// HashFNV1a32 一次性计算 data 的 32 位 FNV-1a 哈希。
+func HashFNV1a32(data []byte) uint32 {
+	h := NewFNV1a32()
+	h.Write(data)
+	return h.Sum32()
+}
+
+// HashFNV1a64 一次性计算 data 的 64 位 FNV-1a 哈希。
+func HashFNV1a64(data []byte) uint64 {
+	h := NewFNV1a64()
+	h.Write(data)
+	return h.Sum64()
+}
+
+// HashXXH64 一次性计算 data 的 xxHash64 哈希。
+func HashXXH64(data []byte, seed uint64) uint64 {
+	h := NewXXHash64(seed)
+	h.Write(data)
+	return h.Sum64()
+}
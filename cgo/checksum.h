@@ -0,0 +1,62 @@
+#ifndef CHECKSUM_H
+#define CHECKSUM_H
+
+#include <stddef.h>
+#include <stdint.h>
+
+// CRC32 多项式（反射形式）
+#define CRC32_POLY_IEEE       0xEDB88320u
+#define CRC32_POLY_CASTAGNOLI 0x82F63B78u
+
+// CRC32 流式上下文，table 是当前多项式的查找表
+typedef struct {
+    uint32_t poly;
+    uint32_t crc;
+    uint32_t table[256];
+} crc32_ctx;
+
+void crc32_init(crc32_ctx* ctx, uint32_t poly);
+void crc32_update(crc32_ctx* ctx, const uint8_t* data, size_t len);
+uint32_t crc32_final(const crc32_ctx* ctx);
+
+// Adler-32 流式上下文
+typedef struct {
+    uint32_t a;
+    uint32_t b;
+} adler32_ctx;
+
+void adler32_init(adler32_ctx* ctx);
+void adler32_update(adler32_ctx* ctx, const uint8_t* data, size_t len);
+uint32_t adler32_final(const adler32_ctx* ctx);
+
+// FNV-1a 流式上下文
+typedef struct {
+    uint32_t h;
+} fnv1a32_ctx;
+
+typedef struct {
+    uint64_t h;
+} fnv1a64_ctx;
+
+void fnv1a32_init(fnv1a32_ctx* ctx);
+void fnv1a32_update(fnv1a32_ctx* ctx, const uint8_t* data, size_t len);
+uint32_t fnv1a32_final(const fnv1a32_ctx* ctx);
+
+void fnv1a64_init(fnv1a64_ctx* ctx);
+void fnv1a64_update(fnv1a64_ctx* ctx, const uint8_t* data, size_t len);
+uint64_t fnv1a64_final(const fnv1a64_ctx* ctx);
+
+// xxHash64 流式上下文
+typedef struct {
+    uint64_t seed;
+    uint64_t total_len;
+    uint64_t v[4];
+    uint8_t mem[32];
+    uint32_t mem_size;
+} xxh64_ctx;
+
+void xxh64_init(xxh64_ctx* ctx, uint64_t seed);
+void xxh64_update(xxh64_ctx* ctx, const uint8_t* data, size_t len);
+uint64_t xxh64_final(const xxh64_ctx* ctx);
+
+#endif
@@ -0,0 +1,208 @@
+package cgo
+
+import (
+	"hash"
+	"hash/adler32"
+	"hash/crc32"
+	"hash/fnv"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 生成固定种子的随机数据，覆盖块边界附近的长度
+func checksumInputs() [][]byte {
+	rng := rand.New(rand.NewSource(1))
+	sizes := []int{0, 1, 3, 4, 7, 8, 31, 32, 33, 63, 64, 100, 5551, 5552, 5553, 65536}
+	inputs := make([][]byte, 0, len(sizes)+1)
+	for _, n := range sizes {
+		b := make([]byte, n)
+		rng.Read(b)
+		inputs = append(inputs, b)
+	}
+	// Adler-32 最坏情况：全部为 0xFF
+	ff := make([]byte, 20000)
+	for i := range ff {
+		ff[i] = 0xFF
+	}
+	return append(inputs, ff)
+}
+
+// 分块写入，验证流式接口与一次性写入结果一致
+func writeChunked(h hash.Hash, data []byte, chunk int) {
+	for len(data) > 0 {
+		n := chunk
+		if n > len(data) {
+			n = len(data)
+		}
+		h.Write(data[:n])
+		data = data[n:]
+	}
+}
+
+// 与标准库实现对比
+func TestChecksumParity(t *testing.T) {
+	castagnoli := crc32.MakeTable(crc32.Castagnoli)
+
+	tests := []struct {
+		name   string
+		native func() hash.Hash
+		golang func() hash.Hash
+	}{
+		{"CRC32 IEEE", func() hash.Hash { return NewCRC32IEEE() }, func() hash.Hash { return crc32.NewIEEE() }},
+		{"CRC32 Castagnoli", func() hash.Hash { return NewCRC32Castagnoli() }, func() hash.Hash { return crc32.New(castagnoli) }},
+		{"Adler32", func() hash.Hash { return NewAdler32() }, func() hash.Hash { return adler32.New() }},
+		{"FNV-1a 32", func() hash.Hash { return NewFNV1a32() }, func() hash.Hash { return fnv.New32a() }},
+		{"FNV-1a 64", func() hash.Hash { return NewFNV1a64() }, func() hash.Hash { return fnv.New64a() }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, data := range checksumInputs() {
+				want := tt.golang()
+				want.Write(data)
+
+				got := tt.native()
+				got.Write(data)
+				assert.Equal(t, want.Sum(nil), got.Sum(nil), "len=%d", len(data))
+
+				for _, chunk := range []int{1, 7, 32, 4096} {
+					streamed := tt.native()
+					writeChunked(streamed, data, chunk)
+					assert.Equal(t, want.Sum(nil), streamed.Sum(nil), "len=%d chunk=%d", len(data), chunk)
+				}
+			}
+		})
+	}
+}
+
+func TestChecksumOneShot(t *testing.T) {
+	data := []byte("The quick brown fox jumps over the lazy dog")
+
+	assert.Equal(t, crc32.ChecksumIEEE(data), ChecksumCRC32(data, CRC32IEEEPoly))
+	assert.Equal(t, crc32.Checksum(data, crc32.MakeTable(crc32.Castagnoli)), ChecksumCRC32(data, CRC32CastagnoliPoly))
+	assert.Equal(t, adler32.Checksum(data), ChecksumAdler32(data))
+
+	f32 := fnv.New32a()
+	f32.Write(data)
+	assert.Equal(t, f32.Sum32(), HashFNV1a32(data))
+
+	f64 := fnv.New64a()
+	f64.Write(data)
+	assert.Equal(t, f64.Sum64(), HashFNV1a64(data))
+}
+
+// 预先生成的表之外的多项式在创建时生成查找表，不能悄悄按 IEEE 计算
+func TestCRC32OtherPoly(t *testing.T) {
+	koopman := crc32.MakeTable(crc32.Koopman)
+	for _, data := range checksumInputs() {
+		want := crc32.Checksum(data, koopman)
+		assert.Equal(t, want, ChecksumCRC32(data, crc32.Koopman))
+
+		h := NewCRC32(crc32.Koopman)
+		writeChunked(h, data, 7)
+		assert.Equal(t, want, h.Sum32())
+		h.Reset()
+		h.Write(data)
+		assert.Equal(t, want, h.Sum32())
+	}
+}
+
+// xxHash64 标准库没有实现，使用官方参考实现的已知结果
+func TestXXHash64(t *testing.T) {
+	tests := []struct {
+		input string
+		seed  uint64
+		want  uint64
+	}{
+		{"", 0, 0xEF46DB3751D8E999},
+		{"a", 0, 0xD24EC4F1A98C6E5B},
+		{"abc", 0, 0x44BC2CF5AD770999},
+		{"Nobody inspects the spammish repetition", 0, 0xFBCEA83C8A378BF1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.want, HashXXH64([]byte(tt.input), tt.seed))
+		})
+	}
+
+	t.Run("Streaming", func(t *testing.T) {
+		for _, data := range checksumInputs() {
+			want := HashXXH64(data, 42)
+			for _, chunk := range []int{1, 5, 31, 32, 33, 1000} {
+				h := NewXXHash64(42)
+				writeChunked(h, data, chunk)
+				assert.Equal(t, want, h.Sum64(), "len=%d chunk=%d", len(data), chunk)
+			}
+		}
+	})
+
+	t.Run("Seed", func(t *testing.T) {
+		data := []byte("seeded")
+		assert.NotEqual(t, HashXXH64(data, 0), HashXXH64(data, 1))
+	})
+}
+
+func TestChecksumHashInterface(t *testing.T) {
+	t.Run("Sum Does Not Change State", func(t *testing.T) {
+		h := NewXXHash64(0)
+		h.Write([]byte("hello "))
+		_ = h.Sum(nil)
+		h.Write([]byte("world"))
+		assert.Equal(t, HashXXH64([]byte("hello world"), 0), h.Sum64())
+	})
+
+	t.Run("Reset", func(t *testing.T) {
+		h := NewCRC32IEEE()
+		h.Write([]byte("garbage"))
+		h.Reset()
+		h.Write([]byte("data"))
+		assert.Equal(t, crc32.ChecksumIEEE([]byte("data")), h.Sum32())
+	})
+
+	t.Run("Sum Appends", func(t *testing.T) {
+		h := NewFNV1a64()
+		out := h.Sum([]byte{0xAA})
+		assert.Len(t, out, 1+h.Size())
+		assert.Equal(t, byte(0xAA), out[0])
+	})
+}
+
+// 原生实现与 Go 实现的性能对比
+func BenchmarkChecksum(b *testing.B) {
+	data := make([]byte, 64*1024)
+	rand.New(rand.NewSource(1)).Read(data)
+	castagnoli := crc32.MakeTable(crc32.Castagnoli)
+
+	benchmarks := []struct {
+		name string
+		new  func() hash.Hash
+	}{
+		{"CGo CRC32 IEEE", func() hash.Hash { return NewCRC32IEEE() }},
+		{"Go CRC32 IEEE", func() hash.Hash { return crc32.NewIEEE() }},
+		{"CGo CRC32 Castagnoli", func() hash.Hash { return NewCRC32Castagnoli() }},
+		{"Go CRC32 Castagnoli", func() hash.Hash { return crc32.New(castagnoli) }},
+		{"CGo Adler32", func() hash.Hash { return NewAdler32() }},
+		{"Go Adler32", func() hash.Hash { return adler32.New() }},
+		{"CGo FNV-1a 32", func() hash.Hash { return NewFNV1a32() }},
+		{"Go FNV-1a 32", func() hash.Hash { return fnv.New32a() }},
+		{"CGo FNV-1a 64", func() hash.Hash { return NewFNV1a64() }},
+		{"Go FNV-1a 64", func() hash.Hash { return fnv.New64a() }},
+		{"CGo xxHash64", func() hash.Hash { return NewXXHash64(0) }},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			h := bm.new()
+			b.SetBytes(int64(len(data)))
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				h.Reset()
+				h.Write(data)
+				h.Sum(nil)
+			}
+		})
+	}
+}
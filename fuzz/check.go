@@ -0,0 +1,106 @@
+package fuzz
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Mismatch 是原字符串中参与比较的一个字符，Start、End 是它在原字符串中的字节范围。
+type Mismatch struct {
+	Start int
+	End   int
+	Rune  rune
+}
+
+// Result 是 CheckPalindrome 的检查结果。
+type Result struct {
+	// Input 是原始输入
+	Input string
+	// Normalized 是过滤并转小写后参与比较的字符串
+	Normalized string
+	// Palindrome 表示是否是回文
+	Palindrome bool
+	// Left、Right 是从两端数起第一对不相等的字符，仅在 Palindrome 为 false 时有效
+	Left, Right Mismatch
+}
+
+// CheckPalindrome 按 IsPalindrome 的规则检查字符串，不是回文时给出第一对不匹配字符在原字符串中的位置。
+func CheckPalindrome(s string) Result {
+	var letters []Mismatch
+	var sb strings.Builder
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if lower := unicode.ToLower(r); unicode.IsLetter(lower) {
+			letters = append(letters, Mismatch{Start: i, End: i + size, Rune: r})
+			sb.WriteRune(lower)
+		}
+		i += size
+	}
+
+	res := Result{Input: s, Normalized: sb.String(), Palindrome: true}
+	for i, j := 0, len(letters)-1; i < j; i, j = i+1, j-1 {
+		if unicode.ToLower(letters[i].Rune) != unicode.ToLower(letters[j].Rune) {
+			res.Palindrome = false
+			res.Left, res.Right = letters[i], letters[j]
+			break
+		}
+	}
+	return res
+}
+
+// Diagnostic 渲染两行诊断信息：第一行是原字符串，第二行用 ^ 标出不匹配的两个字符。
+// 是回文时返回空字符串。换行等控制字符显示为空格，制表符原样保留以保证对齐。
+func (r Result) Diagnostic() string {
+	if r.Palindrome {
+		return ""
+	}
+	var line, carets strings.Builder
+	for i := 0; i < len(r.Input); {
+		c, size := utf8.DecodeRuneInString(r.Input[i:])
+		switch {
+		case c == '\t':
+			line.WriteByte('\t')
+			carets.WriteByte('\t')
+			i += size
+			continue
+		case unicode.IsControl(c), c == utf8.RuneError && size == 1:
+			c = ' '
+		}
+		line.WriteRune(c)
+
+		mark := " "
+		if i == r.Left.Start || i == r.Right.Start {
+			mark = "^"
+		}
+		switch w := displayWidth(c); w {
+		case 0:
+		case 1:
+			carets.WriteString(mark)
+		default:
+			carets.WriteString(mark + strings.Repeat(" ", w-1))
+		}
+		i += size
+	}
+	return line.String() + "\n" + strings.TrimRight(carets.String(), " ")
+}
+
+// displayWidth 返回字符在等宽终端中的显示宽度：组合符号为 0，东亚宽字符和表情为 2。
+func displayWidth(r rune) int {
+	switch {
+	case unicode.In(r, unicode.Mn, unicode.Me), r == 0x200B, r == 0x200D:
+		return 0
+	case r >= 0x1100 && r <= 0x115F,
+		r >= 0x2E80 && r <= 0xA4CF && r != 0x303F,
+		r >= 0xAC00 && r <= 0xD7A3,
+		r >= 0xF900 && r <= 0xFAFF,
+		r >= 0xFE30 && r <= 0xFE4F,
+		r >= 0xFF00 && r <= 0xFF60,
+		r >= 0xFFE0 && r <= 0xFFE6,
+		r >= 0x1F300 && r <= 0x1F64F,
+		r >= 0x1F900 && r <= 0x1F9FF,
+		r >= 0x20000 && r <= 0x3FFFD:
+		return 2
+	}
+	return 1
+}
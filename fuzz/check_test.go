@@ -0,0 +1,84 @@
+package fuzz
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestCheckPalindrome(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		palindrome bool
+		normalized string
+		left       Mismatch
+		right      Mismatch
+	}{
+		{"Palindrome", "Madam, I'm Adam", true, "madamimadam", Mismatch{}, Mismatch{}},
+		{"Empty", "", true, "", Mismatch{}, Mismatch{}},
+		{"Outer Mismatch", "abca", false, "abca", Mismatch{1, 2, 'b'}, Mismatch{2, 3, 'c'}},
+		{"Offsets Skip Punctuation", "A, b... C: a", false, "abca", Mismatch{3, 4, 'b'}, Mismatch{8, 9, 'C'}},
+		{"Multibyte", "上海自来水", false, "上海自来水", Mismatch{0, 3, '上'}, Mismatch{12, 15, '水'}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CheckPalindrome(tt.input)
+			if got.Palindrome != tt.palindrome || got.Normalized != tt.normalized {
+				t.Fatalf("CheckPalindrome(%q) = %+v, want palindrome=%v normalized=%q",
+					tt.input, got, tt.palindrome, tt.normalized)
+			}
+			if !tt.palindrome && (got.Left != tt.left || got.Right != tt.right) {
+				t.Errorf("CheckPalindrome(%q) mismatch = %+v, %+v, want %+v, %+v",
+					tt.input, got.Left, got.Right, tt.left, tt.right)
+			}
+		})
+	}
+}
+
+func TestPalindromeDiagnostic(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"Palindrome", "racecar", ""},
+		{"ASCII", "race a car", "race a car\n   ^ ^"},
+		{"Wide Characters", "上海x来水", "上海x来水\n^      ^"},
+		{"Tab And Newline", "ab\tc\nd", "ab\tc d\n^ \t  ^"},
+		{"Combining Mark", "xe\u0301y", "xe\u0301y\n^ ^"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CheckPalindrome(tt.input).Diagnostic(); got != tt.want {
+				t.Errorf("Diagnostic() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func FuzzCheckPalindrome(f *testing.F) {
+	f.Add("race a car")
+	f.Add("上海自来水")
+
+	f.Fuzz(func(t *testing.T, input string) {
+		res := CheckPalindrome(input)
+		if res.Palindrome != IsPalindrome(input) {
+			t.Fatalf("CheckPalindrome(%q).Palindrome = %v, IsPalindrome = %v", input, res.Palindrome, IsPalindrome(input))
+		}
+		if res.Palindrome {
+			return
+		}
+		// 不匹配的位置必须落在原字符串的字符边界上，并且确实对应报告的字符
+		for _, m := range []Mismatch{res.Left, res.Right} {
+			r, size := utf8.DecodeRuneInString(input[m.Start:])
+			if r != m.Rune || m.Start+size != m.End {
+				t.Errorf("CheckPalindrome(%q) reported %+v, but input has %q there", input, m, r)
+			}
+		}
+		if res.Left.Start >= res.Right.Start {
+			t.Errorf("CheckPalindrome(%q) left %+v is not before right %+v", input, res.Left, res.Right)
+		}
+	})
+}
@@ -0,0 +1,188 @@
+package fuzz
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"unicode"
+)
+
+// normalizeLetters 按 IsPalindrome 的规则转小写并只保留字母
+func normalizeLetters(s string) []rune {
+	rs := make([]rune, 0, len(s))
+	for _, r := range s {
+		if lower := unicode.ToLower(r); unicode.IsLetter(lower) {
+			rs = append(rs, lower)
+		}
+	}
+	return rs
+}
+
+// MinInsertionsToPalindrome 返回把 s 变成回文所需的最少插入次数，以及插入后得到的一个回文。
+// 与 IsPalindrome 相同，只考虑转小写后的字母，返回的回文也是规范化后的形式。
+func MinInsertionsToPalindrome(s string) (int, string) {
+	rs := normalizeLetters(s)
+	n := len(rs)
+	if n == 0 {
+		return 0, ""
+	}
+
+	// dp[i][j] 是把 rs[i..j] 变成回文所需的最少插入次数
+	dp := make([][]int, n)
+	for i := range dp {
+		dp[i] = make([]int, n)
+	}
+	for length := 2; length <= n; length++ {
+		for i := 0; i+length-1 < n; i++ {
+			j := i + length - 1
+			if rs[i] == rs[j] {
+				dp[i][j] = dp[i+1][j-1]
+			} else {
+				dp[i][j] = 1 + min(dp[i+1][j], dp[i][j-1])
+			}
+		}
+	}
+
+	// 从外向内还原：left 收集左半部分，中间最多剩下一个字符
+	left := make([]rune, 0, n)
+	mid := ""
+	for i, j := 0, n-1; i <= j; {
+		switch {
+		case i == j:
+			mid = string(rs[i])
+			i++
+		case rs[i] == rs[j]:
+			left = append(left, rs[i])
+			i, j = i+1, j-1
+		case dp[i+1][j] <= dp[i][j-1]:
+			// 在右侧补一个 rs[i]
+			left = append(left, rs[i])
+			i++
+		default:
+			// 在左侧补一个 rs[j]
+			left = append(left, rs[j])
+			j--
+		}
+	}
+	var sb strings.Builder
+	sb.WriteString(string(left))
+	sb.WriteString(mid)
+	for k := len(left) - 1; k >= 0; k-- {
+		sb.WriteRune(left[k])
+	}
+	return dp[0][n-1], sb.String()
+}
+
+// IsKPalindrome 判断 s 能否通过最多删除 k 个字符变成回文，规范化规则与 IsPalindrome 相同。
+// 等价于 s 与其反转之间只用删除操作的编辑距离不超过 2k，此时最优路径偏离对角线不会超过 k，
+// 因此只需计算宽度为 2k+1 的带状区域，时间复杂度 O(n·k)。
+func IsKPalindrome(s string, k int) bool {
+	if k < 0 {
+		return false
+	}
+	rs := normalizeLetters(s)
+	n := len(rs)
+	if k >= n-1 {
+		// 只剩一个字符时一定是回文
+		return true
+	}
+
+	const inf = 1 << 30
+	width := 2*k + 1
+	// prev、curr 的第 d 项对应列 j = i + d - k
+	prev := make([]int, width)
+	curr := make([]int, width)
+	for d := range prev {
+		j := d - k
+		prev[d] = inf
+		if j >= 0 {
+			prev[d] = j
+		}
+	}
+	for i := 1; i <= n; i++ {
+		rowMin := inf
+		for d := 0; d < width; d++ {
+			j := i + d - k
+			switch {
+			case j < 0 || j > n:
+				curr[d] = inf
+				continue
+			case j == 0:
+				curr[d] = i
+			default:
+				best := inf
+				if rs[i-1] == rs[n-j] {
+					best = prev[d]
+				}
+				// 删除 rs 中的字符：来自上一行同一列
+				if d+1 < width && prev[d+1] != inf {
+					best = min(best, prev[d+1]+1)
+				}
+				// 删除反转串中的字符：来自同一行前一列
+				if d > 0 && curr[d-1] != inf {
+					best = min(best, curr[d-1]+1)
+				}
+				curr[d] = best
+			}
+			rowMin = min(rowMin, curr[d])
+		}
+		if rowMin > 2*k {
+			return false
+		}
+		prev, curr = curr, prev
+	}
+	return prev[k] <= 2*k
+}
+
+// ErrInvalidNumber 表示 NearestPalindromicNumber 的输入不是十进制非负整数。
+var ErrInvalidNumber = errors.New("invalid number")
+
+// NearestPalindromicNumber 返回与 n 最接近且不等于 n 的回文数，距离相同时返回较小的一个。
+// n 是任意长度的十进制非负整数，不允许前导零。
+func NearestPalindromicNumber(n string) (string, error) {
+	if n == "" || len(n) > 1 && n[0] == '0' || strings.TrimLeft(n, "0123456789") != "" {
+		return "", ErrInvalidNumber
+	}
+	num, _ := new(big.Int).SetString(n, 10)
+	if len(n) == 1 {
+		if n == "0" {
+			return "1", nil
+		}
+		return big.NewInt(num.Int64() - 1).String(), nil
+	}
+
+	l := len(n)
+	ten := big.NewInt(10)
+	candidates := []*big.Int{
+		// 位数减一的最大回文，如 99
+		new(big.Int).Sub(new(big.Int).Exp(ten, big.NewInt(int64(l-1)), nil), big.NewInt(1)),
+		// 位数加一的最小回文，如 1001
+		new(big.Int).Add(new(big.Int).Exp(ten, big.NewInt(int64(l)), nil), big.NewInt(1)),
+	}
+	prefix, _ := new(big.Int).SetString(n[:(l+1)/2], 10)
+	for _, delta := range []int64{-1, 0, 1} {
+		p := new(big.Int).Add(prefix, big.NewInt(delta))
+		if p.Sign() <= 0 {
+			continue
+		}
+		ps := p.String()
+		mirror := ps
+		if l%2 == 1 {
+			mirror = ps[:len(ps)-1]
+		}
+		c, _ := new(big.Int).SetString(ps+reverse(mirror), 10)
+		candidates = append(candidates, c)
+	}
+
+	var best, bestDist *big.Int
+	for _, c := range candidates {
+		if c.Cmp(num) == 0 {
+			continue
+		}
+		dist := new(big.Int).Abs(new(big.Int).Sub(c, num))
+		if best == nil || dist.Cmp(bestDist) < 0 || dist.Cmp(bestDist) == 0 && c.Cmp(best) < 0 {
+			best, bestDist = c, dist
+		}
+	}
+	return best.String(), nil
+}
@@ -0,0 +1,163 @@
+package fuzz
+
+import (
+	"strconv"
+	"testing"
+)
+
+// bruteMinDeletions 递归计算变成回文所需的最少删除次数，等于最少插入次数
+func bruteMinDeletions(rs []rune) int {
+	if len(rs) <= 1 {
+		return 0
+	}
+	if rs[0] == rs[len(rs)-1] {
+		return bruteMinDeletions(rs[1 : len(rs)-1])
+	}
+	return 1 + min(bruteMinDeletions(rs[1:]), bruteMinDeletions(rs[:len(rs)-1]))
+}
+
+// isSubsequence 判断 sub 是否是 s 的子序列
+func isSubsequence(sub, s []rune) bool {
+	i := 0
+	for _, r := range s {
+		if i < len(sub) && sub[i] == r {
+			i++
+		}
+	}
+	return i == len(sub)
+}
+
+func TestMinInsertionsToPalindrome(t *testing.T) {
+	tests := []struct {
+		input string
+		count int
+		want  string
+	}{
+		{"", 0, ""},
+		{"Racecar", 0, "racecar"},
+		{"ab", 1, "aba"},
+		{"zzazz", 0, "zzazz"},
+		{"mbadm", 2, "mbadabm"},
+		{"Leet, code!", 5, "leetcodocteel"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			count, got := MinInsertionsToPalindrome(tt.input)
+			if count != tt.count || got != tt.want {
+				t.Errorf("MinInsertionsToPalindrome(%q) = %d, %q, want %d, %q", tt.input, count, got, tt.count, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsKPalindrome(t *testing.T) {
+	tests := []struct {
+		input string
+		k     int
+		want  bool
+	}{
+		{"abcdeca", 2, true},
+		{"abcdeca", 1, false},
+		{"abbababa", 1, true},
+		{"A man, a plan, a canal: Panama", 0, true},
+		{"abc", 0, false},
+		{"abc", 1, false},
+		{"abc", 2, true},
+		{"", 0, true},
+		{"abc", -1, false},
+	}
+
+	for _, tt := range tests {
+		if got := IsKPalindrome(tt.input, tt.k); got != tt.want {
+			t.Errorf("IsKPalindrome(%q, %d) = %v, want %v", tt.input, tt.k, got, tt.want)
+		}
+	}
+}
+
+func TestNearestPalindromicNumber(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"0", "1"},
+		{"1", "0"},
+		{"9", "8"},
+		{"10", "9"},
+		{"11", "9"},
+		{"99", "101"},
+		{"123", "121"},
+		{"1000", "999"},
+		{"12932", "12921"},
+		{"807045053224792883", "807045053350540708"},
+		{"99999999999999999999", "100000000000000000001"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := NearestPalindromicNumber(tt.input)
+			if err != nil || got != tt.want {
+				t.Errorf("NearestPalindromicNumber(%q) = %q, %v, want %q", tt.input, got, err, tt.want)
+			}
+		})
+	}
+
+	for _, invalid := range []string{"", "-1", "012", "1a", "１２"} {
+		if _, err := NearestPalindromicNumber(invalid); err != ErrInvalidNumber {
+			t.Errorf("NearestPalindromicNumber(%q) error = %v, want %v", invalid, err, ErrInvalidNumber)
+		}
+	}
+}
+
+func FuzzMinInsertionsToPalindrome(f *testing.F) {
+	f.Add("mbadm", 1)
+	f.Add("Leet, code!", 3)
+
+	f.Fuzz(func(t *testing.T, input string, k int) {
+		rs := normalizeLetters(input)
+		// 参考实现是指数级的，只比较短输入
+		if len(rs) > 12 || k < -1 || k > 8 {
+			t.Skip()
+		}
+		want := bruteMinDeletions(rs)
+
+		count, pal := MinInsertionsToPalindrome(input)
+		if count != want {
+			t.Errorf("MinInsertionsToPalindrome(%q) count = %d, want %d", input, count, want)
+		}
+		palRunes := []rune(pal)
+		if !IsPalindrome(pal) || len(palRunes) != len(rs)+count || !isSubsequence(rs, palRunes) {
+			t.Errorf("MinInsertionsToPalindrome(%q) = %q is not a valid minimal extension", input, pal)
+		}
+
+		if got := IsKPalindrome(input, k); got != (k >= 0 && want <= k) {
+			t.Errorf("IsKPalindrome(%q, %d) = %v, min deletions %d", input, k, got, want)
+		}
+	})
+}
+
+func FuzzNearestPalindromicNumber(f *testing.F) {
+	f.Add(uint32(123))
+	f.Add(uint32(99))
+
+	f.Fuzz(func(t *testing.T, n uint32) {
+		n %= 200000
+		isPal := func(x int) bool {
+			s := strconv.Itoa(x)
+			return s == reverse(s)
+		}
+		want := -1
+		for d := 1; want < 0; d++ {
+			if int(n)-d >= 0 && isPal(int(n)-d) {
+				want = int(n) - d
+			} else if isPal(int(n) + d) {
+				want = int(n) + d
+			}
+		}
+
+		got, err := NearestPalindromicNumber(strconv.Itoa(int(n)))
+		if err != nil || got != strconv.Itoa(want) {
+			t.Errorf("NearestPalindromicNumber(%d) = %q, %v, want %d", n, got, err, want)
+		}
+	})
+}
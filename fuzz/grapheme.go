@@ -0,0 +1,165 @@
+package fuzz
+
+import (
+	"strings"
+	"unicode"
+)
+
+// graphemeProp 是 UAX #29 中的字素簇断行属性，这里只实现了其中的一个子集。
+type graphemeProp int
+
+const (
+	gpOther graphemeProp = iota
+	gpCR
+	gpLF
+	gpControl
+	gpExtend
+	gpZWJ
+	gpRegionalIndicator
+	gpSpacingMark
+	gpL
+	gpV
+	gpT
+	gpLV
+	gpLVT
+	gpPictographic
+)
+
+// graphemeProperty 返回字符的断行属性。
+// Extended_Pictographic 用 So 类别和表情符号区段近似。
+func graphemeProperty(r rune) graphemeProp {
+	switch {
+	case r == '\r':
+		return gpCR
+	case r == '\n':
+		return gpLF
+	case r == 0x200D:
+		return gpZWJ
+	case r == 0x200C, r >= 0x1F3FB && r <= 0x1F3FF, r >= 0xE0020 && r <= 0xE007F:
+		// ZWNJ、肤色修饰符和标签字符都属于 Extend
+		return gpExtend
+	case r >= 0x1F1E6 && r <= 0x1F1FF:
+		return gpRegionalIndicator
+	case r >= 0x1100 && r <= 0x115F, r >= 0xA960 && r <= 0xA97C:
+		return gpL
+	case r >= 0x1160 && r <= 0x11A7, r >= 0xD7B0 && r <= 0xD7C6:
+		return gpV
+	case r >= 0x11A8 && r <= 0x11FF, r >= 0xD7CB && r <= 0xD7FB:
+		return gpT
+	case r >= 0xAC00 && r <= 0xD7A3:
+		if (r-0xAC00)%28 == 0 {
+			return gpLV
+		}
+		return gpLVT
+	case r > unicode.MaxRune:
+		// decodeRunes 产生的非法字节
+		return gpControl
+	case unicode.In(r, unicode.Mn, unicode.Me):
+		return gpExtend
+	case unicode.Is(unicode.Mc, r):
+		return gpSpacingMark
+	case unicode.In(r, unicode.Cc, unicode.Cf, unicode.Zl, unicode.Zp):
+		return gpControl
+	case r >= 0x1F000 && r <= 0x1FAFF, unicode.Is(unicode.So, r):
+		return gpPictographic
+	}
+	return gpOther
+}
+
+// graphemeBounds 返回字符序列中每个字素簇的起始下标，最后追加 len(rs)。
+func graphemeBounds(rs []rune) []int {
+	bounds := make([]int, 0, len(rs)+1)
+	var prev graphemeProp
+	// pictSeq 表示当前处于 ExtPict Extend* 序列中（GB11）
+	pictSeq := false
+	// riCount 是紧邻当前位置之前连续区域指示符的个数（GB12、GB13）
+	riCount := 0
+	for i, r := range rs {
+		curr := graphemeProperty(r)
+		if i == 0 || graphemeBreak(prev, curr, pictSeq, riCount) {
+			bounds = append(bounds, i)
+		}
+
+		switch curr {
+		case gpPictographic:
+			pictSeq = true
+		case gpExtend, gpZWJ:
+			// ZWJ 之后只能紧跟 ExtPict
+			pictSeq = pictSeq && prev != gpZWJ
+		default:
+			pictSeq = false
+		}
+		if curr == gpRegionalIndicator {
+			riCount++
+		} else {
+			riCount = 0
+		}
+		prev = curr
+	}
+	return append(bounds, len(rs))
+}
+
+// graphemeBreak 判断 prev 与 curr 之间是否断开。
+func graphemeBreak(prev, curr graphemeProp, pictSeq bool, riCount int) bool {
+	switch {
+	case prev == gpCR && curr == gpLF: // GB3
+		return false
+	case prev == gpCR, prev == gpLF, prev == gpControl: // GB4
+		return true
+	case curr == gpCR, curr == gpLF, curr == gpControl: // GB5
+		return true
+	case prev == gpL && (curr == gpL || curr == gpV || curr == gpLV || curr == gpLVT): // GB6
+		return false
+	case (prev == gpLV || prev == gpV) && (curr == gpV || curr == gpT): // GB7
+		return false
+	case (prev == gpLVT || prev == gpT) && curr == gpT: // GB8
+		return false
+	case curr == gpExtend, curr == gpZWJ, curr == gpSpacingMark: // GB9、GB9a
+		return false
+	case prev == gpZWJ && curr == gpPictographic && pictSeq: // GB11
+		return false
+	case prev == gpRegionalIndicator && curr == gpRegionalIndicator: // GB12、GB13
+		return riCount%2 == 0
+	}
+	return true // GB999
+}
+
+// GraphemeClusters 把字符串切分为字素簇，拼接结果等于原字符串。
+func GraphemeClusters(s string) []string {
+	rs, offsets := decodeRunes(s)
+	bounds := graphemeBounds(rs)
+	clusters := make([]string, 0, len(bounds)-1)
+	for k := 0; k+1 < len(bounds); k++ {
+		clusters = append(clusters, s[offsets[bounds[k]]:offsets[bounds[k+1]]])
+	}
+	return clusters
+}
+
+// ReverseGraphemes 按字素簇反转字符串，组合附加符号、ZWJ 表情序列和国旗不会被拆开。
+func ReverseGraphemes(s string) string {
+	clusters := GraphemeClusters(s)
+	var sb strings.Builder
+	sb.Grow(len(s))
+	for i := len(clusters) - 1; i >= 0; i-- {
+		sb.WriteString(clusters[i])
+	}
+	return sb.String()
+}
+
+// filterGraphemes 以字素簇为单位过滤：保留以字母、数字（可选）或表情符号开头的簇，
+// 同时返回保留下来的各簇的起始下标，避免过滤后相邻字符重新组合成新的簇。
+func filterGraphemes(rs []rune, includeDigits bool) ([]rune, []int) {
+	bounds := graphemeBounds(rs)
+	out := make([]rune, 0, len(rs))
+	kept := make([]int, 0, len(bounds))
+	for k := 0; k+1 < len(bounds); k++ {
+		first := rs[bounds[k]]
+		prop := graphemeProperty(first)
+		if unicode.IsLetter(first) || includeDigits && unicode.IsDigit(first) ||
+			prop == gpPictographic || prop == gpRegionalIndicator {
+			kept = append(kept, len(out))
+			out = append(out, rs[bounds[k]:bounds[k+1]]...)
+		}
+	}
+	return out, append(kept, len(out))
+}
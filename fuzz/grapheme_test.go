@@ -0,0 +1,105 @@
+package fuzz
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGraphemeClusters(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{"Empty", "", []string{}},
+		{"ASCII", "abc", []string{"a", "b", "c"}},
+		{"Combining Mark", "e\u0301a", []string{"e\u0301", "a"}},
+		{"Multiple Marks", "a\u0323\u0302b", []string{"a\u0323\u0302", "b"}},
+		{"CRLF", "a\r\nb", []string{"a", "\r\n", "b"}},
+		{"Leading Mark", "\u0301a", []string{"\u0301", "a"}},
+		{"Flags", "\U0001F1FA\U0001F1F8\U0001F1EB\U0001F1F7", []string{"\U0001F1FA\U0001F1F8", "\U0001F1EB\U0001F1F7"}},
+		{"Odd Regional Indicators", "\U0001F1FA\U0001F1F8\U0001F1EB", []string{"\U0001F1FA\U0001F1F8", "\U0001F1EB"}},
+		{"ZWJ Family", "\U0001F468\u200D\U0001F469\u200D\U0001F467x", []string{"\U0001F468\u200D\U0001F469\u200D\U0001F467", "x"}},
+		{"Skin Tone", "\U0001F44D\U0001F3FD\U0001F44D", []string{"\U0001F44D\U0001F3FD", "\U0001F44D"}},
+		{"ZWJ Without Emoji", "a\u200Db", []string{"a\u200D", "b"}},
+		{"Hangul Jamo", "\u1100\u1161\u11A8\uAC00", []string{"\u1100\u1161\u11A8", "\uAC00"}},
+		{"Invalid UTF-8", "a\xffb", []string{"a", "\xff", "b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GraphemeClusters(tt.input); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GraphemeClusters(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReverseGraphemes(t *testing.T) {
+	tests := []struct {
+		input, want string
+	}{
+		{"", ""},
+		{"abc", "cba"},
+		{"cafe\u0301", "e\u0301fac"},
+		{"\U0001F1FA\U0001F1F8\U0001F1EB\U0001F1F7", "\U0001F1EB\U0001F1F7\U0001F1FA\U0001F1F8"},
+		{"\U0001F468\u200D\U0001F469\u200D\U0001F467 hi", "ih \U0001F468\u200D\U0001F469\u200D\U0001F467"},
+		{"a\r\nb", "b\r\na"},
+	}
+
+	for _, tt := range tests {
+		if got := ReverseGraphemes(tt.input); got != tt.want {
+			t.Errorf("ReverseGraphemes(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestIsPalindromeGraphemes(t *testing.T) {
+	graphemes := PalindromeOptions{Graphemes: true}
+	tests := []struct {
+		name  string
+		input string
+		opts  PalindromeOptions
+		want  bool
+	}{
+		// 按字符反转时 🇺🇸 会变成 🇸🇺，因此字符级比较会误判为回文
+		{"Flags Reversed", "\U0001F1FA\U0001F1F8\U0001F1F8\U0001F1FA", graphemes, false},
+		{"Flags Palindrome", "\U0001F1FA\U0001F1F8 x \U0001F1FA\U0001F1F8", graphemes, true},
+		{"ZWJ Family", "\U0001F468\u200D\U0001F469\u200D\U0001F467 a \U0001F468\u200D\U0001F469\u200D\U0001F467", graphemes, true},
+		{"ZWJ Family Order", "\U0001F468\u200D\U0001F469\u200D\U0001F467 a \U0001F467\u200D\U0001F469\u200D\U0001F468", graphemes, false},
+		{"Combining Marks", "e\u0301xe\u0301", graphemes, true},
+		{"Letters Still Filtered", "A man, a plan, a canal: Panama", graphemes, true},
+		{"Digits", "1a2", PalindromeOptions{Graphemes: true, IncludeDigits: true}, false},
+		{"NFC With Graphemes", "\u00e9xe\u0301", PalindromeOptions{Graphemes: true, Normalization: NFC}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsPalindromeWithOptions(tt.input, tt.opts); got != tt.want {
+				t.Errorf("IsPalindromeWithOptions(%q, %+v) = %v, want %v", tt.input, tt.opts, got, tt.want)
+			}
+		})
+	}
+}
+
+func FuzzReverseGraphemes(f *testing.F) {
+	f.Add("cafe\u0301")
+	f.Add("\U0001F1FA\U0001F1F8\U0001F1EB\U0001F1F7")
+	f.Add("\U0001F468\u200D\U0001F469\u200D\U0001F467")
+
+	f.Fuzz(func(t *testing.T, input string) {
+		clusters := GraphemeClusters(input)
+		if joined := strings.Join(clusters, ""); joined != input {
+			t.Fatalf("GraphemeClusters(%q) joined = %q", input, joined)
+		}
+		for _, c := range clusters {
+			if c == "" {
+				t.Fatalf("GraphemeClusters(%q) produced empty cluster", input)
+			}
+		}
+		if reversed := ReverseGraphemes(input); len(reversed) != len(input) {
+			t.Errorf("ReverseGraphemes(%q) = %q, length changed", input, reversed)
+		}
+	})
+}
@@ -0,0 +1,97 @@
+package fuzz
+
+// Locale 是大小写转换使用的语言环境，对应 SpecialCasing.txt 中的语言标签。
+type Locale string
+
+const (
+	LocaleRoot       Locale = ""   // 与语言无关的默认规则
+	LocaleTurkish    Locale = "tr" // 土耳其语
+	LocaleAzeri      Locale = "az" // 阿塞拜疆语
+	LocaleLithuanian Locale = "lt" // 立陶宛语
+)
+
+const (
+	combiningDotAbove = 0x0307
+	// cccAbove 是上方附加符号的规范组合类
+	cccAbove = 230
+)
+
+// lithuanianLower 是立陶宛语中无条件生效的小写映射：带重音的大写 I 转小写时保留上方的点
+var lithuanianLower = map[rune][]rune{
+	0x00CC: {'i', combiningDotAbove, 0x0300}, // Ì
+	0x00CD: {'i', combiningDotAbove, 0x0301}, // Í
+	0x0128: {'i', combiningDotAbove, 0x0303}, // Ĩ
+}
+
+// localeLower 按 SpecialCasing.txt 中与语言相关的规则转换 rs[i]，
+// 没有适用的规则时 ok 为 false，由调用方使用默认规则。
+func localeLower(rs []rune, i int, loc Locale) (mapped []rune, ok bool) {
+	r := rs[i]
+	switch loc {
+	case LocaleTurkish, LocaleAzeri:
+		switch {
+		case r == 0x0130: // İ → i
+			return []rune{'i'}, true
+		case r == combiningDotAbove && afterI(rs, i): // I 之后的上点被吸收
+			return nil, true
+		case r == 'I' && !beforeDot(rs, i): // I → ı
+			return []rune{'ı'}, true
+		}
+	case LocaleLithuanian:
+		if m, found := lithuanianLower[r]; found {
+			return m, true
+		}
+		if !moreAbove(rs, i) {
+			return nil, false
+		}
+		// 后面还有上方附加符号时补上点，避免小写 i、j、į 的点被重音取代
+		switch r {
+		case 'I':
+			return []rune{'i', combiningDotAbove}, true
+		case 'J':
+			return []rune{'j', combiningDotAbove}, true
+		case 0x012E: // Į
+			return []rune{0x012F, combiningDotAbove}, true
+		}
+	}
+	return nil, false
+}
+
+// afterI 对应 After_I 条件：前面有大写 I，且中间没有组合类为 0 或 230 的字符。
+func afterI(rs []rune, i int) bool {
+	for j := i - 1; j >= 0; j-- {
+		if rs[j] == 'I' {
+			return true
+		}
+		if cc := combiningClass(rs[j]); cc == 0 || cc == cccAbove {
+			return false
+		}
+	}
+	return false
+}
+
+// beforeDot 对应 Before_Dot 条件：后面跟着 U+0307，中间没有组合类为 0 或 230 的字符。
+func beforeDot(rs []rune, i int) bool {
+	for j := i + 1; j < len(rs); j++ {
+		if rs[j] == combiningDotAbove {
+			return true
+		}
+		if cc := combiningClass(rs[j]); cc == 0 || cc == cccAbove {
+			return false
+		}
+	}
+	return false
+}
+
+// moreAbove 对应 More_Above 条件：后面跟着组合类为 230 的字符，中间没有组合类为 0 的字符。
+func moreAbove(rs []rune, i int) bool {
+	for j := i + 1; j < len(rs); j++ {
+		switch combiningClass(rs[j]) {
+		case cccAbove:
+			return true
+		case 0:
+			return false
+		}
+	}
+	return false
+}
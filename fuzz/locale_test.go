@@ -0,0 +1,84 @@
+package fuzz
+
+import "testing"
+
+// 用例来自 SpecialCasing.txt 中与语言相关的小写规则，每条规则同时给出满足和不满足条件的输入
+func TestLocaleLower(t *testing.T) {
+	tests := []struct {
+		rule   string
+		locale Locale
+		input  string
+		want   string
+	}{
+		// 0130; 0069; 0130; 0130; tr/az;
+		{"tr Dotted I", LocaleTurkish, "\u0130", "i"},
+		{"az Dotted I", LocaleAzeri, "\u0130", "i"},
+		// 0307; ; 0307; 0307; tr/az After_I;
+		{"tr After_I", LocaleTurkish, "I\u0307", "i"},
+		{"az After_I", LocaleAzeri, "I\u0307", "i"},
+		{"tr After_I With Below Mark", LocaleTurkish, "I\u0323\u0307", "i\u0323"},
+		{"tr Not After_I", LocaleTurkish, "a\u0307", "a\u0307"},
+		{"tr After_I Blocked By Above Mark", LocaleTurkish, "I\u0301\u0307", "\u0131\u0301\u0307"},
+		// 0049; 0131; 0049; 0049; tr/az Not_Before_Dot;
+		{"tr Not_Before_Dot", LocaleTurkish, "I", "ı"},
+		{"az Not_Before_Dot", LocaleAzeri, "KIRIK", "kırık"},
+		{"tr Dotless Stays", LocaleTurkish, "ıi", "ıi"},
+		// 0049; 0069 0307; 0049; 0049; lt More_Above;
+		{"lt More_Above I", LocaleLithuanian, "I\u0300", "i\u0307\u0300"},
+		{"lt Not More_Above I", LocaleLithuanian, "I", "i"},
+		{"lt More_Above Blocked By Base", LocaleLithuanian, "Ia\u0300", "ia\u0300"},
+		{"lt More_Above After Below Mark", LocaleLithuanian, "I\u0328\u0301", "i\u0307\u0328\u0301"},
+		// 004A; 006A 0307; 004A; 004A; lt More_Above;
+		{"lt More_Above J", LocaleLithuanian, "J\u0303", "j\u0307\u0303"},
+		// 012E; 012F 0307; 012E; 012E; lt More_Above;
+		{"lt More_Above I Ogonek", LocaleLithuanian, "\u012E\u0301", "\u012F\u0307\u0301"},
+		// 00CC; 0069 0307 0300; 00CC; 00CC; lt;
+		{"lt I Grave", LocaleLithuanian, "\u00CC", "i\u0307\u0300"},
+		// 00CD; 0069 0307 0301; 00CD; 00CD; lt;
+		{"lt I Acute", LocaleLithuanian, "\u00CD", "i\u0307\u0301"},
+		// 0128; 0069 0307 0303; 0128; 0128; lt;
+		{"lt I Tilde", LocaleLithuanian, "\u0128", "i\u0307\u0303"},
+		// 默认规则
+		{"Root I", LocaleRoot, "I", "i"},
+		{"Root Dotted I", LocaleRoot, "\u0130", "i"},
+		{"Root I Grave", LocaleRoot, "\u00CC", "\u00EC"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			if got := string(mapCase([]rune(tt.input), false, tt.locale)); got != tt.want {
+				t.Errorf("mapCase(%+q, %q) = %+q, want %+q", tt.input, tt.locale, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsPalindromeLocale(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		opts  PalindromeOptions
+		want  bool
+	}{
+		{"Root Dotless Mismatch", "I\u0131", PalindromeOptions{}, false},
+		{"Turkish Dotless", "I\u0131", PalindromeOptions{Locale: LocaleTurkish}, true},
+		{"Turkish Dotted", "\u0130i", PalindromeOptions{Locale: LocaleTurkish}, true},
+		{"Turkish I Is Not i", "Ii", PalindromeOptions{Locale: LocaleTurkish}, false},
+		{"Turkish Decomposed Dotted I", "I\u0307xi", PalindromeOptions{Locale: LocaleTurkish}, true},
+		{"Turkish NFD", "\u0130xI\u0307", PalindromeOptions{Locale: LocaleTurkish, Normalization: NFD}, true},
+		{"Azeri Folding", "IxI\u0131", PalindromeOptions{Locale: LocaleAzeri, FoldCase: true}, false},
+		{"Azeri Folding Palindrome", "Ix\u0131", PalindromeOptions{Locale: LocaleAzeri, FoldCase: true}, true},
+		{"Root Folding Keeps Dotless", "Ix\u0131", PalindromeOptions{FoldCase: true}, false},
+		{"Lithuanian Grave", "\u00CCxi\u0307\u0300", PalindromeOptions{Locale: LocaleLithuanian}, true},
+		{"Root Grave", "\u00CCxi\u0307\u0300", PalindromeOptions{}, false},
+		{"Lithuanian Decomposed Grave", "I\u0300xi\u0307\u0300", PalindromeOptions{Locale: LocaleLithuanian, Normalization: NFD}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsPalindromeWithOptions(tt.input, tt.opts); got != tt.want {
+				t.Errorf("IsPalindromeWithOptions(%+q, %+v) = %v, want %v", tt.input, tt.opts, got, tt.want)
+			}
+		})
+	}
+}
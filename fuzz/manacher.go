@@ -0,0 +1,115 @@
+package fuzz
+
+import "unicode/utf8"
+
+// Palindrome 描述原字符串中的一个回文子串，Start、End 是字节偏移，子串为 s[Start:End]。
+type Palindrome struct {
+	Start int
+	End   int
+	Text  string
+}
+
+// decodeRunes 把字符串解码为字符序列，并返回每个字符在原字符串中的起始字节偏移，
+// offsets 比 runes 多一项，最后一项为 len(s)。
+// 非法 UTF-8 字节按单字节处理，映射到 Unicode 范围之外的值，保证不同的非法字节互不相等。
+func decodeRunes(s string) (runes []rune, offsets []int) {
+	runes = make([]rune, 0, len(s))
+	offsets = make([]int, 0, len(s)+1)
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			r = utf8.MaxRune + 1 + rune(s[i])
+		}
+		runes = append(runes, r)
+		offsets = append(offsets, i)
+		i += size
+	}
+	offsets = append(offsets, len(s))
+	return runes, offsets
+}
+
+// manacher 计算以每个字符为中心的奇回文半径 odd（包含中心字符），
+// 以及以第 i-1、i 个字符之间为中心的偶回文半径 even，时间复杂度 O(n)。
+func manacher(rs []rune) (odd, even []int) {
+	n := len(rs)
+	odd = make([]int, n)
+	for i, l, r := 0, 0, -1; i < n; i++ {
+		k := 1
+		if i <= r {
+			k = min(odd[l+r-i], r-i+1)
+		}
+		for i-k >= 0 && i+k < n && rs[i-k] == rs[i+k] {
+			k++
+		}
+		odd[i] = k
+		if i+k-1 > r {
+			l, r = i-k+1, i+k-1
+		}
+	}
+
+	even = make([]int, n)
+	for i, l, r := 0, 0, -1; i < n; i++ {
+		k := 0
+		if i <= r {
+			k = min(even[l+r-i+1], r-i+1)
+		}
+		for i-k-1 >= 0 && i+k < n && rs[i-k-1] == rs[i+k] {
+			k++
+		}
+		even[i] = k
+		if i+k-1 > r {
+			l, r = i-k, i+k-1
+		}
+	}
+	return odd, even
+}
+
+// LongestPalindrome 使用 Manacher 算法返回 s 中最长的回文子串（按字符数计），
+// 长度相同时返回最靠左的一个。字符严格比较，不做大小写和字符过滤。
+func LongestPalindrome(s string) Palindrome {
+	rs, offsets := decodeRunes(s)
+	odd, even := manacher(rs)
+
+	best, bestStart := 0, 0
+	for i := range rs {
+		if length := 2*odd[i] - 1; length > best || length == best && i-odd[i]+1 < bestStart {
+			best, bestStart = length, i-odd[i]+1
+		}
+		if length := 2 * even[i]; length > best || length == best && length > 0 && i-even[i] < bestStart {
+			best, bestStart = length, i-even[i]
+		}
+	}
+	start, end := offsets[bestStart], offsets[bestStart+best]
+	return Palindrome{Start: start, End: end, Text: s[start:end]}
+}
+
+// CountPalindromicSubstrings 返回 s 中回文子串的个数，位置不同的相同子串分别计数。
+func CountPalindromicSubstrings(s string) int {
+	rs, _ := decodeRunes(s)
+	odd, even := manacher(rs)
+	count := 0
+	for i := range rs {
+		count += odd[i] + even[i]
+	}
+	return count
+}
+
+// AllMaximalPalindromes 返回每个中心上无法再向两侧扩展的回文子串，按中心位置从左到右排列，
+// 字符之间的中心只有在回文非空时才会出现在结果中。
+func AllMaximalPalindromes(s string) []Palindrome {
+	rs, offsets := decodeRunes(s)
+	odd, even := manacher(rs)
+
+	result := make([]Palindrome, 0, 2*len(rs))
+	add := func(from, to int) {
+		start, end := offsets[from], offsets[to]
+		result = append(result, Palindrome{Start: start, End: end, Text: s[start:end]})
+	}
+	for i := range rs {
+		if even[i] > 0 {
+			add(i-even[i], i+even[i])
+		}
+		add(i-odd[i]+1, i+odd[i])
+	}
+	return result
+}
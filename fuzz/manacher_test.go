@@ -0,0 +1,141 @@
+package fuzz
+
+import (
+	"reflect"
+	"testing"
+)
+
+// bruteForcePalindromes 枚举所有子串作为参考实现，返回最长回文、回文个数和所有极大回文
+func bruteForcePalindromes(s string) (Palindrome, int, []Palindrome) {
+	rs, offsets := decodeRunes(s)
+	isPal := func(i, j int) bool {
+		for ; i < j; i, j = i+1, j-1 {
+			if rs[i] != rs[j] {
+				return false
+			}
+		}
+		return true
+	}
+	mk := func(i, j int) Palindrome {
+		return Palindrome{Start: offsets[i], End: offsets[j], Text: s[offsets[i]:offsets[j]]}
+	}
+
+	longest, longestLen := mk(0, 0), 0
+	count := 0
+	for i := range rs {
+		for j := i; j < len(rs); j++ {
+			if isPal(i, j) {
+				count++
+				// 只有严格更长时才替换，保证长度相同时取最靠左的
+				if j-i+1 > longestLen {
+					longest, longestLen = mk(i, j+1), j-i+1
+				}
+			}
+		}
+	}
+
+	var maximal []Palindrome
+	for c := 0; c < 2*len(rs)-1; c++ {
+		// 中心 c 为偶数时是第 c/2 个字符，为奇数时在第 c/2 与 c/2+1 个字符之间
+		i, j := c/2, (c+1)/2
+		if i != j && rs[i] != rs[j] {
+			continue
+		}
+		for i > 0 && j < len(rs)-1 && rs[i-1] == rs[j+1] {
+			i, j = i-1, j+1
+		}
+		maximal = append(maximal, mk(i, j+1))
+	}
+	return longest, count, maximal
+}
+
+func TestLongestPalindrome(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Palindrome
+	}{
+		{"", Palindrome{0, 0, ""}},
+		{"a", Palindrome{0, 1, "a"}},
+		{"babad", Palindrome{0, 3, "bab"}},
+		{"cbbd", Palindrome{1, 3, "bb"}},
+		{"forgeeksskeegfor", Palindrome{3, 13, "geeksskeeg"}},
+		{"abc", Palindrome{0, 1, "a"}},
+		{"x上海自来水来自海上y", Palindrome{1, 28, "上海自来水来自海上"}},
+		{"héllé", Palindrome{1, 7, "éllé"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := LongestPalindrome(tt.input); got != tt.want {
+				t.Errorf("LongestPalindrome(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCountPalindromicSubstrings(t *testing.T) {
+	tests := []struct {
+		input string
+		want  int
+	}{
+		{"", 0},
+		{"abc", 3},
+		{"aaa", 6},
+		{"上上", 3},
+	}
+
+	for _, tt := range tests {
+		if got := CountPalindromicSubstrings(tt.input); got != tt.want {
+			t.Errorf("CountPalindromicSubstrings(%q) = %d, want %d", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestAllMaximalPalindromes(t *testing.T) {
+	got := AllMaximalPalindromes("abba")
+	want := []Palindrome{
+		{0, 1, "a"},
+		{1, 2, "b"},
+		{0, 4, "abba"},
+		{2, 3, "b"},
+		{3, 4, "a"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("AllMaximalPalindromes(%q) = %+v, want %+v", "abba", got, want)
+	}
+}
+
+// 非法 UTF-8 字节互不相等，且偏移量仍然指向原字符串
+func TestPalindromeInvalidUTF8(t *testing.T) {
+	if got := LongestPalindrome("\xff\xfe\xff"); got.Text != "\xff\xfe\xff" {
+		t.Errorf("LongestPalindrome = %q, want whole string", got.Text)
+	}
+	if got := LongestPalindrome("\xff\xfe"); got.Text != "\xff" {
+		t.Errorf("LongestPalindrome = %q, want %q", got.Text, "\xff")
+	}
+}
+
+func FuzzLongestPalindrome(f *testing.F) {
+	f.Add("babad")
+	f.Add("aaaa")
+	f.Add("上海自来水来自海上")
+	f.Add("a\xffa")
+
+	f.Fuzz(func(t *testing.T, input string) {
+		// 参考实现是 O(n³)，限制输入长度
+		if len(input) > 64 {
+			t.Skip()
+		}
+		wantLongest, wantCount, wantMaximal := bruteForcePalindromes(input)
+
+		if got := LongestPalindrome(input); got != wantLongest {
+			t.Errorf("LongestPalindrome(%q) = %+v, want %+v", input, got, wantLongest)
+		}
+		if got := CountPalindromicSubstrings(input); got != wantCount {
+			t.Errorf("CountPalindromicSubstrings(%q) = %d, want %d", input, got, wantCount)
+		}
+		if got := AllMaximalPalindromes(input); len(got) != len(wantMaximal) || len(got) > 0 && !reflect.DeepEqual(got, wantMaximal) {
+			t.Errorf("AllMaximalPalindromes(%q) = %+v, want %+v", input, got, wantMaximal)
+		}
+	})
+}
@@ -0,0 +1,236 @@
+package fuzz
+
+import (
+	"sort"
+	"unicode"
+)
+
+// NormalizationForm 表示比较前使用的 Unicode 规范化形式。
+type NormalizationForm int
+
+const (
+	NormNone NormalizationForm = iota // 不做规范化
+	NFC                               // 规范分解后再规范组合
+	NFD                               // 规范分解
+)
+
+// PalindromeOptions 控制回文判断时的字符过滤和大小写、规范化规则。
+// 零值表示：只保留字母、逐字符转小写、不做规范化。
+type PalindromeOptions struct {
+	// IncludeDigits 为 true 时数字也参与比较
+	IncludeDigits bool
+	// FoldCase 为 true 时使用完整的 Unicode 大小写折叠（如 ß → ss、ς → σ），
+	// 否则逐字符使用 unicode.ToLower
+	FoldCase bool
+	// Normalization 指定规范化形式，目前只内置了拉丁字母的分解表
+	Normalization NormalizationForm
+	// StripDiacritics 为 true 时去掉附加符号（é → e、ø → o）
+	StripDiacritics bool
+	// Graphemes 为 true 时以字素簇为比较单位，表情符号和国旗等非字母簇也参与比较
+	Graphemes bool
+	// Locale 指定大小写转换的语言环境，例如土耳其语中 I 的小写是 ı
+	Locale Locale
+}
+
+// IsPalindromeWithOptions 按 opts 处理字符串后判断是否是回文。
+// 与 IsPalindrome 不同，组合附加符号不会被丢弃，而是和前面的基字符作为一个整体比较。
+func IsPalindromeWithOptions(s string, opts PalindromeOptions) bool {
+	return isUnitPalindrome(normalizeUnits(s, opts))
+}
+
+// normalizeUnits 是回文判断的规范化流程：分解 → 去附加符号 → 大小写 → 组合 → 过滤，
+// 返回参与比较的字符序列以及每个比较单位的起始下标（最后一项为 len(rs)）。
+func normalizeUnits(s string, opts PalindromeOptions) (rs []rune, bounds []int) {
+	rs = []rune(s)
+	if opts.Normalization != NormNone || opts.StripDiacritics {
+		rs = decompose(rs)
+	}
+	if opts.StripDiacritics {
+		rs = stripDiacritics(rs)
+	}
+	rs = mapCase(rs, opts.FoldCase, opts.Locale)
+	if opts.Normalization == NFC {
+		rs = compose(rs)
+	}
+	if opts.Graphemes {
+		return filterGraphemes(rs, opts.IncludeDigits)
+	}
+	rs = filterRunes(rs, opts.IncludeDigits)
+	return rs, markBounds(rs)
+}
+
+// isMark 判断是否是组合附加符号
+func isMark(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc)
+}
+
+// combiningClass 返回字符的规范组合类，表外字符视为 0（起始字符）。
+func combiningClass(r rune) uint8 {
+	return combiningClasses[r]
+}
+
+// latinCompositions 是 latinDecompositions 的逆映射。
+var latinCompositions = func() map[[2]rune]rune {
+	m := make(map[[2]rune]rune, len(latinDecompositions))
+	for composed, pair := range latinDecompositions {
+		m[pair] = composed
+	}
+	return m
+}()
+
+// decompose 对字符序列做规范分解（NFD），并按组合类对附加符号排序。
+func decompose(rs []rune) []rune {
+	out := make([]rune, 0, len(rs))
+	var expand func(r rune)
+	expand = func(r rune) {
+		if pair, ok := latinDecompositions[r]; ok {
+			expand(pair[0])
+			out = append(out, pair[1])
+			return
+		}
+		out = append(out, r)
+	}
+	for _, r := range rs {
+		expand(r)
+	}
+
+	// 规范排序：连续的非起始字符按组合类稳定排序
+	for i := 0; i < len(out); {
+		if combiningClass(out[i]) == 0 {
+			i++
+			continue
+		}
+		j := i
+		for j < len(out) && combiningClass(out[j]) != 0 {
+			j++
+		}
+		seq := out[i:j]
+		sort.SliceStable(seq, func(a, b int) bool {
+			return combiningClass(seq[a]) < combiningClass(seq[b])
+		})
+		i = j
+	}
+	return out
+}
+
+// compose 对已分解的字符序列做规范组合（NFC）。
+func compose(rs []rune) []rune {
+	out := make([]rune, 0, len(rs))
+	starter := -1
+	// lastCC 是起始字符之后最后一个未被组合字符的组合类，-1 表示紧跟起始字符
+	lastCC := -1
+	for _, r := range rs {
+		cc := int(combiningClass(r))
+		if starter >= 0 && cc != 0 && (lastCC == -1 || lastCC < cc) {
+			if composed, ok := latinCompositions[[2]rune{out[starter], r}]; ok {
+				out[starter] = composed
+				continue
+			}
+		}
+		out = append(out, r)
+		if cc == 0 {
+			starter = len(out) - 1
+			lastCC = -1
+		} else {
+			lastCC = cc
+		}
+	}
+	return out
+}
+
+// strokeLetters 是没有规范分解、但通常视为带附加符号的字母。
+var strokeLetters = map[rune]rune{
+	'Ø': 'O', 'ø': 'o',
+	'Đ': 'D', 'đ': 'd',
+	'Ł': 'L', 'ł': 'l',
+	'Ħ': 'H', 'ħ': 'h',
+	'Ŧ': 'T', 'ŧ': 't',
+	'Ƀ': 'B', 'ƀ': 'b',
+	'Ɨ': 'I', 'ɨ': 'i',
+}
+
+// stripDiacritics 去掉已分解序列中的非间距附加符号。
+func stripDiacritics(rs []rune) []rune {
+	out := rs[:0]
+	for _, r := range rs {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		if base, ok := strokeLetters[r]; ok {
+			r = base
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+// mapCase 转换大小写：先应用 loc 的特殊规则，fold 为 true 时做完整大小写折叠，否则逐字符转小写。
+func mapCase(rs []rune, fold bool, loc Locale) []rune {
+	out := make([]rune, 0, len(rs))
+	for i, r := range rs {
+		if mapped, ok := localeLower(rs, i, loc); ok {
+			out = append(out, mapped...)
+			continue
+		}
+		if !fold {
+			out = append(out, unicode.ToLower(r))
+			continue
+		}
+		if s, ok := fullCaseFoldings[r]; ok {
+			out = append(out, []rune(s)...)
+			continue
+		}
+		out = append(out, simpleFold(r))
+	}
+	return out
+}
+
+// simpleFold 返回字符的简单大小写折叠结果，例如 ς、Σ 都折叠为 σ，K（开尔文符号）折叠为 k。
+// 无点的 ı 在 CaseFolding.txt 中没有折叠映射，保持不变。
+func simpleFold(r rune) rune {
+	if r == 'ı' {
+		return r
+	}
+	return unicode.ToLower(unicode.ToUpper(r))
+}
+
+// filterRunes 只保留字母（以及可选的数字），附加符号跟随其基字符保留或丢弃。
+func filterRunes(rs []rune, includeDigits bool) []rune {
+	out := rs[:0]
+	attached := false
+	for _, r := range rs {
+		switch {
+		case isMark(r):
+			if attached {
+				out = append(out, r)
+			}
+		case unicode.IsLetter(r), includeDigits && unicode.IsDigit(r):
+			out = append(out, r)
+			attached = true
+		default:
+			attached = false
+		}
+	}
+	return out
+}
+
+// markBounds 把基字符和其后的附加符号视为一个单位，返回每个单位的起始下标，最后追加 len(rs)。
+func markBounds(rs []rune) []int {
+	bounds := make([]int, 0, len(rs)+1)
+	for i, r := range rs {
+		if i == 0 || !isMark(r) {
+			bounds = append(bounds, i)
+		}
+	}
+	return append(bounds, len(rs))
+}
+
+// isUnitPalindrome 按 bounds 划分的单位从两端向中间比较。
+func isUnitPalindrome(rs []rune, bounds []int) bool {
+	for i, j := 0, len(bounds)-2; i < j; i, j = i+1, j-1 {
+		if string(rs[bounds[i]:bounds[i+1]]) != string(rs[bounds[j]:bounds[j+1]]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,78 @@
+package fuzz
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestIsPalindromeWithOptions(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		opts  PalindromeOptions
+		want  bool
+	}{
+		{"Zero Options", "A man, a plan, a canal: Panama", PalindromeOptions{}, true},
+		{"Digits Ignored", "a12a", PalindromeOptions{}, true},
+		{"Digits Included", "a12a", PalindromeOptions{IncludeDigits: true}, false},
+		{"Digits Palindrome", "No. 1221 on", PalindromeOptions{IncludeDigits: true}, true},
+		{"Sharp S Without Folding", "Sß", PalindromeOptions{}, false},
+		{"Sharp S With Folding", "Sß", PalindromeOptions{FoldCase: true}, true},
+		{"Final Sigma Without Folding", "ςοΣ", PalindromeOptions{}, false},
+		{"Final Sigma With Folding", "ςοΣ", PalindromeOptions{FoldCase: true}, true},
+		{"Kelvin Sign With Folding", "\u212aak", PalindromeOptions{FoldCase: true}, true},
+		{"Dotless I Not Folded", "ıI", PalindromeOptions{FoldCase: true}, false},
+		{"Mixed Forms Without Normalization", "\u00e9t e\u0301", PalindromeOptions{}, false},
+		{"Mixed Forms NFC", "\u00e9t e\u0301", PalindromeOptions{Normalization: NFC}, true},
+		{"Mixed Forms NFD", "\u00e9t e\u0301", PalindromeOptions{Normalization: NFD}, true},
+		{"Multi Level Decomposition", "\u1ea5 x a\u0302\u0301", PalindromeOptions{Normalization: NFD}, true},
+		{"Canonical Ordering", "a\u0323\u0302 x a\u0302\u0323", PalindromeOptions{Normalization: NFC}, true},
+		{"Distinct Mark Order", "a\u0302\u0301 x a\u0301\u0302", PalindromeOptions{Normalization: NFD}, false},
+		{"Diacritics Kept", "Ésope reste ici et se repose", PalindromeOptions{}, false},
+		{"Diacritics Stripped", "Ésope reste ici et se repose", PalindromeOptions{StripDiacritics: true}, true},
+		{"Stroke Letters Stripped", "Øo", PalindromeOptions{StripDiacritics: true}, true},
+		{"Combining Mark Stays With Base", "e\u0301e", PalindromeOptions{}, false},
+		{"Empty", "", PalindromeOptions{FoldCase: true, Normalization: NFC}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsPalindromeWithOptions(tt.input, tt.opts); got != tt.want {
+				t.Errorf("IsPalindromeWithOptions(%q, %+v) = %v, want %v", tt.input, tt.opts, got, tt.want)
+			}
+		})
+	}
+}
+
+// 表中每个预组合字符分解后再组合应得到自身
+func TestLatinNormalizationRoundTrip(t *testing.T) {
+	for composed := range latinDecompositions {
+		decomposed := decompose([]rune{composed})
+		for _, r := range decomposed[1:] {
+			if combiningClass(r) == 0 {
+				t.Fatalf("decompose(%U) = %U, want base followed by marks", composed, decomposed)
+			}
+		}
+		if got := compose(decomposed); len(got) != 1 || got[0] != composed {
+			t.Errorf("compose(decompose(%U)) = %U", composed, got)
+		}
+	}
+}
+
+func FuzzIsPalindromeWithOptions(f *testing.F) {
+	f.Add("Ésope reste ici et se repose")
+	f.Add("ét é")
+	f.Add("Sß")
+
+	f.Fuzz(func(t *testing.T, input string) {
+		if !utf8.ValidString(input) {
+			t.Skip()
+		}
+		// NFC 和 NFD 是规范等价的，判断结果必须一致
+		nfc := IsPalindromeWithOptions(input, PalindromeOptions{Normalization: NFC, FoldCase: true})
+		nfd := IsPalindromeWithOptions(input, PalindromeOptions{Normalization: NFD, FoldCase: true})
+		if nfc != nfd {
+			t.Errorf("input %q: NFC result %v differs from NFD result %v", input, nfc, nfd)
+		}
+	})
+}
@@ -0,0 +1,102 @@
+package fuzz
+
+import (
+	"slices"
+	"strings"
+)
+
+// CanPermuteToPalindrome 判断 s 规范化后的字母能否重新排列成回文，
+// 即出现奇数次的字母最多只有一个。规范化规则与 IsPalindrome 相同。
+func CanPermuteToPalindrome(s string) bool {
+	odd := make(map[rune]bool)
+	for _, r := range normalizeLetters(s) {
+		odd[r] = !odd[r]
+	}
+	count := 0
+	for _, isOdd := range odd {
+		if isOdd {
+			count++
+		}
+	}
+	return count <= 1
+}
+
+// PalindromicPermutations 按字典序返回 s 规范化后的字母能组成的所有不同回文，
+// 最多返回 limit 个，limit <= 0 表示不限制。无法组成回文时返回 nil。
+func PalindromicPermutations(s string, limit int) []string {
+	rs := normalizeLetters(s)
+	if !CanPermuteToPalindrome(s) {
+		return nil
+	}
+
+	slices.Sort(rs)
+	// 每种字母取一半组成 half，出现奇数次的字母放在中间
+	half := make([]rune, 0, len(rs)/2)
+	mid := ""
+	for i := 0; i < len(rs); {
+		j := i
+		for j < len(rs) && rs[j] == rs[i] {
+			j++
+		}
+		for k := 0; k < (j-i)/2; k++ {
+			half = append(half, rs[i])
+		}
+		if (j-i)%2 == 1 {
+			mid = string(rs[i])
+		}
+		i = j
+	}
+
+	var result []string
+	for {
+		var sb strings.Builder
+		sb.WriteString(string(half))
+		sb.WriteString(mid)
+		for k := len(half) - 1; k >= 0; k-- {
+			sb.WriteRune(half[k])
+		}
+		result = append(result, sb.String())
+		if limit > 0 && len(result) >= limit || !nextPermutation(half) {
+			return result
+		}
+	}
+}
+
+// nextPermutation 把 rs 原地变为字典序的下一个排列，已经是最后一个排列时返回 false。
+// 重复元素只会产生不同的排列。
+func nextPermutation(rs []rune) bool {
+	i := len(rs) - 2
+	for i >= 0 && rs[i] >= rs[i+1] {
+		i--
+	}
+	if i < 0 {
+		return false
+	}
+	j := len(rs) - 1
+	for rs[j] <= rs[i] {
+		j--
+	}
+	rs[i], rs[j] = rs[j], rs[i]
+	slices.Reverse(rs[i+1:])
+	return true
+}
+
+// GroupAnagrams 把规范化后字母组成相同的单词分为一组，
+// 组按首次出现的顺序排列，组内保持输入顺序。
+func GroupAnagrams(words []string) [][]string {
+	index := make(map[string]int)
+	var groups [][]string
+	for _, w := range words {
+		rs := normalizeLetters(w)
+		slices.Sort(rs)
+		key := string(rs)
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], w)
+	}
+	return groups
+}
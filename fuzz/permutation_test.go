@@ -0,0 +1,114 @@
+package fuzz
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestCanPermuteToPalindrome(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"", true},
+		{"Tact Coa", true},
+		{"carerac", true},
+		{"code", false},
+		{"AaBb", true},
+		{"ÉéÀ", true},
+		{"Σσς", true},
+		{"上海上海水", true},
+	}
+
+	for _, tt := range tests {
+		if got := CanPermuteToPalindrome(tt.input); got != tt.want {
+			t.Errorf("CanPermuteToPalindrome(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestPalindromicPermutations(t *testing.T) {
+	tests := []struct {
+		input string
+		limit int
+		want  []string
+	}{
+		{"", 0, []string{""}},
+		{"abc", 0, nil},
+		{"aabb", 0, []string{"abba", "baab"}},
+		{"AaBbC", 0, []string{"abcba", "bacab"}},
+		{"aabbcc", 0, []string{"abccba", "acbbca", "baccab", "bcaacb", "cabbac", "cbaabc"}},
+		{"aabbcc", 2, []string{"abccba", "acbbca"}},
+		{"Éé上", 0, []string{"é上é"}},
+	}
+
+	for _, tt := range tests {
+		if got := PalindromicPermutations(tt.input, tt.limit); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("PalindromicPermutations(%q, %d) = %q, want %q", tt.input, tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestGroupAnagrams(t *testing.T) {
+	got := GroupAnagrams([]string{"eat", "Tea", "tan", "ATE", "nat", "bat", "Été", "téÉ", "!"})
+	want := [][]string{
+		{"eat", "Tea", "ATE"},
+		{"tan", "nat"},
+		{"bat"},
+		{"Été", "téÉ"},
+		{"!"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GroupAnagrams = %q, want %q", got, want)
+	}
+}
+
+// bruteForcePalindromicPermutations 枚举所有排列，去重后保留回文
+func bruteForcePalindromicPermutations(rs []rune) []string {
+	seen := make(map[string]bool)
+	var permute func(k int)
+	permute = func(k int) {
+		if k == len(rs) {
+			if s := string(rs); IsPalindrome(s) {
+				seen[s] = true
+			}
+			return
+		}
+		for i := k; i < len(rs); i++ {
+			rs[k], rs[i] = rs[i], rs[k]
+			permute(k + 1)
+			rs[k], rs[i] = rs[i], rs[k]
+		}
+	}
+	permute(0)
+
+	var result []string
+	for s := range seen {
+		result = append(result, s)
+	}
+	slices.Sort(result)
+	return result
+}
+
+func FuzzPalindromicPermutations(f *testing.F) {
+	f.Add("aabbc")
+	f.Add("Tact Coa")
+	f.Add("ÉéÀà")
+
+	f.Fuzz(func(t *testing.T, input string) {
+		rs := normalizeLetters(input)
+		if len(rs) > 7 {
+			t.Skip()
+		}
+		want := bruteForcePalindromicPermutations(rs)
+
+		got := PalindromicPermutations(input, 0)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("PalindromicPermutations(%q) = %q, want %q", input, got, want)
+		}
+		if can := CanPermuteToPalindrome(input); can != (len(want) > 0) {
+			t.Errorf("CanPermuteToPalindrome(%q) = %v, brute force found %d", input, can, len(want))
+		}
+	})
+}
@@ -0,0 +1,128 @@
+package fuzz
+
+import (
+	"io"
+	"unicode"
+	"unicode/utf8"
+)
+
+// readerBufSize 是 IsPalindromeReader 每一端使用的缓冲区大小
+const readerBufSize = 32 * 1024
+
+// IsPalindromeReader 从数据两端向中间扫描判断是否是回文，过滤和大小写规则与 IsPalindrome 相同。
+// 两端各只使用一个固定大小的缓冲区，适合无法整体读入内存的大文件。
+// 非法的 UTF-8 字节和 IsPalindrome 一样被忽略。
+func IsPalindromeReader(r io.ReadSeeker) (bool, error) {
+	return isPalindromeReader(r, readerBufSize)
+}
+
+func isPalindromeReader(r io.ReadSeeker, bufSize int) (bool, error) {
+	bufSize = max(bufSize, utf8.UTFMax)
+	size, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return false, err
+	}
+
+	front := &forwardScanner{rs: r, buf: make([]byte, bufSize)}
+	back := &backwardScanner{rs: r, buf: make([]byte, bufSize), start: size - int64(bufSize)}
+	back.lo, back.hi = bufSize, bufSize
+
+	for {
+		// 两端互相限制扫描范围，避免一端越过另一端读完整个文件
+		front.limit = back.pos()
+		fr, ok, err := front.nextLetter()
+		if err != nil || !ok {
+			return err == nil, err
+		}
+		back.limit = front.pos()
+		br, ok, err := back.prevLetter()
+		if err != nil || !ok {
+			return err == nil, err
+		}
+		if unicode.ToLower(fr) != unicode.ToLower(br) {
+			return false, nil
+		}
+	}
+}
+
+// readAt 从 off 处读满 p，数据比 Seek 报告的长度短时返回 io.ErrUnexpectedEOF
+func readAt(rs io.ReadSeeker, p []byte, off int64) error {
+	if _, err := rs.Seek(off, io.SeekStart); err != nil {
+		return err
+	}
+	_, err := io.ReadFull(rs, p)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// forwardScanner 从前向后解码字符，buf[lo:hi] 对应数据中 [start+lo, start+hi) 的字节。
+type forwardScanner struct {
+	rs     io.ReadSeeker
+	buf    []byte
+	start  int64
+	lo, hi int
+	limit  int64
+}
+
+func (s *forwardScanner) pos() int64 { return s.start + int64(s.lo) }
+
+// nextLetter 返回下一个字母，到达 limit 时 ok 为 false。
+func (s *forwardScanner) nextLetter() (r rune, ok bool, err error) {
+	for s.pos() < s.limit {
+		// 剩余字节不足一个完整字符时补充数据，保证解码不会截断多字节字符
+		if s.hi-s.lo < utf8.UTFMax && s.start+int64(s.hi) < s.limit {
+			n := copy(s.buf, s.buf[s.lo:s.hi])
+			s.start += int64(s.lo)
+			s.lo, s.hi = 0, n
+			want := min(int64(len(s.buf)-n), s.limit-(s.start+int64(n)))
+			if err := readAt(s.rs, s.buf[n:n+int(want)], s.start+int64(n)); err != nil {
+				return 0, false, err
+			}
+			s.hi += int(want)
+		}
+		r, size := utf8.DecodeRune(s.buf[s.lo:s.hi])
+		s.lo += size
+		if unicode.IsLetter(r) {
+			return r, true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+// backwardScanner 从后向前解码字符，buf[lo:hi] 对应数据中 [start+lo, start+hi) 的字节。
+type backwardScanner struct {
+	rs     io.ReadSeeker
+	buf    []byte
+	start  int64
+	lo, hi int
+	limit  int64
+}
+
+func (s *backwardScanner) pos() int64 { return s.start + int64(s.hi) }
+
+// prevLetter 返回前一个字母，到达 limit 时 ok 为 false。
+func (s *backwardScanner) prevLetter() (r rune, ok bool, err error) {
+	for s.pos() > s.limit {
+		// 向前补充数据，保证 DecodeLastRune 能看到完整的字符起始字节
+		if s.hi-s.lo < utf8.UTFMax && s.start+int64(s.lo) > s.limit {
+			n := s.hi - s.lo
+			copy(s.buf[len(s.buf)-n:], s.buf[s.lo:s.hi])
+			end := s.start + int64(s.hi)
+			s.start = end - int64(len(s.buf))
+			s.lo, s.hi = len(s.buf)-n, len(s.buf)
+			want := min(int64(s.lo), s.start+int64(s.lo)-s.limit)
+			if err := readAt(s.rs, s.buf[s.lo-int(want):s.lo], s.start+int64(s.lo)-want); err != nil {
+				return 0, false, err
+			}
+			s.lo -= int(want)
+		}
+		r, size := utf8.DecodeLastRune(s.buf[s.lo:s.hi])
+		s.hi -= size
+		if unicode.IsLetter(r) {
+			return r, true, nil
+		}
+	}
+	return 0, false, nil
+}
@@ -0,0 +1,110 @@
+package fuzz
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestIsPalindromeReader(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"", true},
+		{"a", true},
+		{"madam", true},
+		{"hello", false},
+		{"A man, a plan, a canal: Panama", true},
+		{"上海自来水来自海上", true},
+		{"上海自来水", false},
+		{"Été, ÉTÉ", true},
+		{"!!!a???", true},
+		{"a\xffb\xfec", false},
+		{"a\xff\xfea", true},
+	}
+
+	for _, tt := range tests {
+		// 用很小的缓冲区覆盖多字节字符跨越缓冲区边界的情况
+		for _, bufSize := range []int{4, 5, 7, 64, readerBufSize} {
+			got, err := isPalindromeReader(strings.NewReader(tt.input), bufSize)
+			if err != nil {
+				t.Fatalf("isPalindromeReader(%q, %d) error: %v", tt.input, bufSize, err)
+			}
+			if got != tt.want {
+				t.Errorf("isPalindromeReader(%q, %d) = %v, want %v", tt.input, bufSize, got, tt.want)
+			}
+		}
+	}
+}
+
+func TestIsPalindromeReaderLargeInput(t *testing.T) {
+	half := strings.Repeat("Abc, 上海. ", 50000)
+	input := half + "x" + ReverseGraphemes(half)
+
+	got, err := IsPalindromeReader(strings.NewReader(input))
+	if err != nil || !got {
+		t.Errorf("IsPalindromeReader(large palindrome) = %v, %v, want true, nil", got, err)
+	}
+
+	got, err = IsPalindromeReader(strings.NewReader(input + "y"))
+	if err != nil || got {
+		t.Errorf("IsPalindromeReader(large non-palindrome) = %v, %v, want false, nil", got, err)
+	}
+}
+
+// failingReadSeeker 在读取时返回错误
+type failingReadSeeker struct {
+	*strings.Reader
+}
+
+var errRead = errors.New("read failed")
+
+func (failingReadSeeker) Read([]byte) (int, error) { return 0, errRead }
+
+func TestIsPalindromeReaderError(t *testing.T) {
+	_, err := IsPalindromeReader(failingReadSeeker{strings.NewReader("abc")})
+	if !errors.Is(err, errRead) {
+		t.Errorf("IsPalindromeReader error = %v, want %v", err, errRead)
+	}
+}
+
+// 数据在扫描过程中被截断时返回 io.ErrUnexpectedEOF
+type shrinkingReadSeeker struct {
+	*strings.Reader
+	size int64
+}
+
+func (s *shrinkingReadSeeker) Seek(offset int64, whence int) (int64, error) {
+	if whence == io.SeekEnd {
+		return s.size, nil
+	}
+	return s.Reader.Seek(offset, whence)
+}
+
+func TestIsPalindromeReaderTruncated(t *testing.T) {
+	r := &shrinkingReadSeeker{Reader: strings.NewReader("abcba"), size: 10}
+	if _, err := isPalindromeReader(r, 4); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("isPalindromeReader error = %v, want %v", err, io.ErrUnexpectedEOF)
+	}
+}
+
+func FuzzIsPalindromeReader(f *testing.F) {
+	f.Add("madam", 4)
+	f.Add("上海自来水来自海上", 5)
+	f.Add("a\xe4\xb8a", 4)
+
+	f.Fuzz(func(t *testing.T, input string, bufSize int) {
+		if bufSize < 0 || bufSize > 1024 {
+			t.Skip()
+		}
+		got, err := isPalindromeReader(strings.NewReader(input), bufSize)
+		if err != nil {
+			t.Fatalf("isPalindromeReader(%q, %d) error: %v", input, bufSize, err)
+		}
+		if want := IsPalindrome(input); got != want {
+			t.Errorf("isPalindromeReader(%q, %d) = %v, IsPalindrome = %v", input, bufSize, got, want)
+		}
+	})
+}
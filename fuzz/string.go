@@ -1,18 +1,49 @@
 package fuzz
 
 import (
-	"strings"
 	"unicode"
+	"unicode/utf8"
 )
 
 // 检查字符串是否是回文
+// 忽略大小写，只比较字母；从两端同时解码 UTF-8 向中间比较，不分配内存。
 func IsPalindrome(s string) bool {
-	// 将字符串转换为小写并去掉空格
-	s = strings.ToLower(s) // 确保忽略大小写
-	s = removeNonAlphanumeric(s)
+	return isPalindromeTwoPointer(s, utf8.DecodeRuneInString, utf8.DecodeLastRuneInString)
+}
+
+// IsPalindromeBytes 与 IsPalindrome 规则相同，直接处理字节切片，不分配内存。
+func IsPalindromeBytes(b []byte) bool {
+	return isPalindromeTwoPointer(b, utf8.DecodeRune, utf8.DecodeLastRune)
+}
 
-	// 判断反转后是否相等
-	return s == reverse(s)
+// isPalindromeTwoPointer 在 s[i:j] 范围内分别从头、尾解码，跳过非字母后比较小写形式。
+func isPalindromeTwoPointer[T string | []byte](s T, first, last func(T) (rune, int)) bool {
+	i, j := 0, len(s)
+	for i < j {
+		l, size := first(s[i:j])
+		i += size
+		if l = unicode.ToLower(l); !unicode.IsLetter(l) {
+			continue
+		}
+
+		found := false
+		for i < j {
+			r, size := last(s[i:j])
+			j -= size
+			if r = unicode.ToLower(r); unicode.IsLetter(r) {
+				if r != l {
+					return false
+				}
+				found = true
+				break
+			}
+		}
+		if !found {
+			// 另一端已经没有字母，l 位于正中间
+			return true
+		}
+	}
+	return true
 }
 
 func reverse(s string) string {
@@ -22,15 +53,3 @@ func reverse(s string) string {
 	}
 	return string(runes)
 }
-
-// removeNonAlphanumeric 移除非字母字符
-func removeNonAlphanumeric(s string) string {
-	var result []rune
-	for _, r := range s {
-		// 只保留字母字符
-		if unicode.IsLetter(r) {
-			result = append(result, r)
-		}
-	}
-	return string(result)
-}
@@ -1,34 +1,146 @@
 package fuzz
 
 import (
+	"strings"
 	"testing"
+	"unicode"
 	"unicode/utf8"
 )
 
 func FuzzIsPalindrome(f *testing.F) {
 
-	// 向测试添加初始值
-	f.Add("hello")
-	f.Add("madam")
-	f.Add("racecar")
-	f.Add("éçà") // 添加包含非ASCII字符的例子
-	f.Add("aA")  // 添加大小写不同的回文字符串
-	//f.Add("A man a plan a canal Panama") // 经典的回文
+	// 向测试添加初始值。testdata/fuzz/FuzzIsPalindrome 中按用例命名的文件是手工挑选的种子，
+	// 以哈希命名的文件是旧性质（result && input != reverse(input)）在模糊测试中得到的最小化失败输入，
+	// 如 "01"、"0A00"，它们是回文但旧性质判为失败，这里保留作为回归用例
+	f.Add("hello", uint(0))
+	f.Add("madam", uint(2))
+	f.Add("racecar", uint(7))
+	f.Add("éçà", uint(1)) // 添加包含非ASCII字符的例子
+	f.Add("aA", uint(1))  // 添加大小写不同的回文字符串
+	f.Add("A man a plan a canal Panama", uint(13))
 
 	// Fuzz() 方法接收一个处理函数，每次执行测试时会传入模糊化的输入
-	f.Fuzz(func(t *testing.T, input string) {
-		// 如果输入包含无效的UTF-8字符，我们跳过
-		if !utf8.ValidString(input) {
-			t.Skipf("Skipping invalid UTF-8 input: %s", input)
-			return
+	f.Fuzz(func(t *testing.T, input string, pos uint) {
+		result := IsPalindrome(input)
+
+		// 与慢速参考实现对比
+		if want := isPalindromeReference(input); result != want {
+			t.Fatalf("IsPalindrome(%q) = %v, reference = %v", input, result, want)
+		}
+		if got := IsPalindromeBytes([]byte(input)); got != result {
+			t.Fatalf("IsPalindromeBytes(%q) = %v, IsPalindrome = %v", input, got, result)
 		}
 
-		// 调用 IsPalindrome 函数，并验证其输出
-		result := IsPalindrome(input)
+		// 反转不变性：字符串反转后结果不变
+		if got := IsPalindrome(reverse(input)); got != result {
+			t.Errorf("IsPalindrome(reverse(%q)) = %v, want %v", input, got, result)
+		}
+
+		// 大小写不变性：把能够往返转换的字符改为大写后结果不变
+		swapped := strings.Map(func(r rune) rune {
+			if upper := unicode.ToUpper(r); unicode.ToLower(upper) == unicode.ToLower(r) {
+				return upper
+			}
+			return r
+		}, input)
+		if got := IsPalindrome(swapped); got != result {
+			t.Errorf("IsPalindrome(%q) = %v, want %v as for %q", swapped, got, result, input)
+		}
+
+		// 插入非字母字符后结果不变，插入位置调整到字符边界上
+		at := int(pos % uint(len(input)+1))
+		for at < len(input) && !utf8.RuneStart(input[at]) {
+			at++
+		}
+		inserted := input[:at] + " ,1!\t" + input[at:]
+		if got := IsPalindrome(inserted); got != result {
+			t.Errorf("IsPalindrome(%q) = %v, want %v as for %q", inserted, got, result, input)
+		}
+	})
+}
+
+// isPalindromeReference 是改为双指针之前的实现，逻辑直观但较慢，
+// 作为模糊测试的判定依据和性能基准
+func isPalindromeReference(s string) bool {
+	s = strings.ToLower(s)
+	s = removeNonAlphanumeric(s)
+	return s == reverse(s)
+}
+
+// removeNonAlphanumeric 移除非字母字符
+func removeNonAlphanumeric(s string) string {
+	var result []rune
+	for _, r := range s {
+		// 只保留字母字符
+		if unicode.IsLetter(r) {
+			result = append(result, r)
+		}
+	}
+	return string(result)
+}
+
+var palindromeCases = []string{
+	"",
+	"a",
+	"Aa",
+	"madam",
+	"hello",
+	"A man, a plan, a canal: Panama",
+	"上海自来水来自海上",
+	"éçà",
+	"Été, ÉTÉ",
+	"a\xffb\xfea",
+	"\xe4\xb8a\xe4",
+	"!!!",
+}
+
+func TestIsPalindromeTwoPointer(t *testing.T) {
+	for _, input := range palindromeCases {
+		want := isPalindromeReference(input)
+		if got := IsPalindrome(input); got != want {
+			t.Errorf("IsPalindrome(%q) = %v, want %v", input, got, want)
+		}
+		if got := IsPalindromeBytes([]byte(input)); got != want {
+			t.Errorf("IsPalindromeBytes(%q) = %v, want %v", input, got, want)
+		}
+	}
+}
+
+func TestIsPalindromeZeroAllocs(t *testing.T) {
+	for _, input := range palindromeCases {
+		b := []byte(input)
+		if allocs := testing.AllocsPerRun(100, func() { IsPalindrome(input) }); allocs != 0 {
+			t.Errorf("IsPalindrome(%q) allocs = %v, want 0", input, allocs)
+		}
+		if allocs := testing.AllocsPerRun(100, func() { IsPalindromeBytes(b) }); allocs != 0 {
+			t.Errorf("IsPalindromeBytes(%q) allocs = %v, want 0", input, allocs)
+		}
+	}
+}
+
+func BenchmarkIsPalindrome(b *testing.B) {
+	input := strings.Repeat("A man, a plan, a canal: Panama. 上海自来水来自海上. ", 20)
+	input += reverse(input)
+	data := []byte(input)
+
+	b.Run("Reference", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			isPalindromeReference(input)
+		}
+	})
+
+	b.Run("String", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			IsPalindrome(input)
+		}
+	})
 
-		// 我们可以对回文字符串做一个断言，检查输出是否正确
-		if result && input != reverse(input) {
-			t.Errorf("For input %s, expected palindrome but got false", input)
+	b.Run("Bytes", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			IsPalindromeBytes(data)
 		}
 	})
 }
@@ -0,0 +1,730 @@
+package fuzz
+
+// 本文件中的表格由 Unicode 14.0 的 UnicodeData.txt 和 CaseFolding.txt 整理而来，
+// 只覆盖拉丁字母相关区段（U+00C0–U+024F、U+1E00–U+1EFF）以及组合附加符号区（U+0300–U+036F）。
+
+// latinDecompositions 是拉丁字母的规范分解映射，每项分解为一个基字符加一个组合符号，
+// 多级分解（如 ấ → â + U+0301 → a + U+0302 + U+0301）在运行时递归展开。
+var latinDecompositions = map[rune][2]rune{
+	0x00C0: {0x0041, 0x0300}, // À
+	0x00C1: {0x0041, 0x0301}, // Á
+	0x00C2: {0x0041, 0x0302}, // Â
+	0x00C3: {0x0041, 0x0303}, // Ã
+	0x00C4: {0x0041, 0x0308}, // Ä
+	0x00C5: {0x0041, 0x030A}, // Å
+	0x00C7: {0x0043, 0x0327}, // Ç
+	0x00C8: {0x0045, 0x0300}, // È
+	0x00C9: {0x0045, 0x0301}, // É
+	0x00CA: {0x0045, 0x0302}, // Ê
+	0x00CB: {0x0045, 0x0308}, // Ë
+	0x00CC: {0x0049, 0x0300}, // Ì
+	0x00CD: {0x0049, 0x0301}, // Í
+	0x00CE: {0x0049, 0x0302}, // Î
+	0x00CF: {0x0049, 0x0308}, // Ï
+	0x00D1: {0x004E, 0x0303}, // Ñ
+	0x00D2: {0x004F, 0x0300}, // Ò
+	0x00D3: {0x004F, 0x0301}, // Ó
+	0x00D4: {0x004F, 0x0302}, // Ô
+	0x00D5: {0x004F, 0x0303}, // Õ
+	0x00D6: {0x004F, 0x0308}, // Ö
+	0x00D9: {0x0055, 0x0300}, // Ù
+	0x00DA: {0x0055, 0x0301}, // Ú
+	0x00DB: {0x0055, 0x0302}, // Û
+	0x00DC: {0x0055, 0x0308}, // Ü
+	0x00DD: {0x0059, 0x0301}, // Ý
+	0x00E0: {0x0061, 0x0300}, // à
+	0x00E1: {0x0061, 0x0301}, // á
+	0x00E2: {0x0061, 0x0302}, // â
+	0x00E3: {0x0061, 0x0303}, // ã
+	0x00E4: {0x0061, 0x0308}, // ä
+	0x00E5: {0x0061, 0x030A}, // å
+	0x00E7: {0x0063, 0x0327}, // ç
+	0x00E8: {0x0065, 0x0300}, // è
+	0x00E9: {0x0065, 0x0301}, // é
+	0x00EA: {0x0065, 0x0302}, // ê
+	0x00EB: {0x0065, 0x0308}, // ë
+	0x00EC: {0x0069, 0x0300}, // ì
+	0x00ED: {0x0069, 0x0301}, // í
+	0x00EE: {0x0069, 0x0302}, // î
+	0x00EF: {0x0069, 0x0308}, // ï
+	0x00F1: {0x006E, 0x0303}, // ñ
+	0x00F2: {0x006F, 0x0300}, // ò
+	0x00F3: {0x006F, 0x0301}, // ó
+	0x00F4: {0x006F, 0x0302}, // ô
+	0x00F5: {0x006F, 0x0303}, // õ
+	0x00F6: {0x006F, 0x0308}, // ö
+	0x00F9: {0x0075, 0x0300}, // ù
+	0x00FA: {0x0075, 0x0301}, // ú
+	0x00FB: {0x0075, 0x0302}, // û
+	0x00FC: {0x0075, 0x0308}, // ü
+	0x00FD: {0x0079, 0x0301}, // ý
+	0x00FF: {0x0079, 0x0308}, // ÿ
+	0x0100: {0x0041, 0x0304}, // Ā
+	0x0101: {0x0061, 0x0304}, // ā
+	0x0102: {0x0041, 0x0306}, // Ă
+	0x0103: {0x0061, 0x0306}, // ă
+	0x0104: {0x0041, 0x0328}, // Ą
+	0x0105: {0x0061, 0x0328}, // ą
+	0x0106: {0x0043, 0x0301}, // Ć
+	0x0107: {0x0063, 0x0301}, // ć
+	0x0108: {0x0043, 0x0302}, // Ĉ
+	0x0109: {0x0063, 0x0302}, // ĉ
+	0x010A: {0x0043, 0x0307}, // Ċ
+	0x010B: {0x0063, 0x0307}, // ċ
+	0x010C: {0x0043, 0x030C}, // Č
+	0x010D: {0x0063, 0x030C}, // č
+	0x010E: {0x0044, 0x030C}, // Ď
+	0x010F: {0x0064, 0x030C}, // ď
+	0x0112: {0x0045, 0x0304}, // Ē
+	0x0113: {0x0065, 0x0304}, // ē
+	0x0114: {0x0045, 0x0306}, // Ĕ
+	0x0115: {0x0065, 0x0306}, // ĕ
+	0x0116: {0x0045, 0x0307}, // Ė
+	0x0117: {0x0065, 0x0307}, // ė
+	0x0118: {0x0045, 0x0328}, // Ę
+	0x0119: {0x0065, 0x0328}, // ę
+	0x011A: {0x0045, 0x030C}, // Ě
+	0x011B: {0x0065, 0x030C}, // ě
+	0x011C: {0x0047, 0x0302}, // Ĝ
+	0x011D: {0x0067, 0x0302}, // ĝ
+	0x011E: {0x0047, 0x0306}, // Ğ
+	0x011F: {0x0067, 0x0306}, // ğ
+	0x0120: {0x0047, 0x0307}, // Ġ
+	0x0121: {0x0067, 0x0307}, // ġ
+	0x0122: {0x0047, 0x0327}, // Ģ
+	0x0123: {0x0067, 0x0327}, // ģ
+	0x0124: {0x0048, 0x0302}, // Ĥ
+	0x0125: {0x0068, 0x0302}, // ĥ
+	0x0128: {0x0049, 0x0303}, // Ĩ
+	0x0129: {0x0069, 0x0303}, // ĩ
+	0x012A: {0x0049, 0x0304}, // Ī
+	0x012B: {0x0069, 0x0304}, // ī
+	0x012C: {0x0049, 0x0306}, // Ĭ
+	0x012D: {0x0069, 0x0306}, // ĭ
+	0x012E: {0x0049, 0x0328}, // Į
+	0x012F: {0x0069, 0x0328}, // į
+	0x0130: {0x0049, 0x0307}, // İ
+	0x0134: {0x004A, 0x0302}, // Ĵ
+	0x0135: {0x006A, 0x0302}, // ĵ
+	0x0136: {0x004B, 0x0327}, // Ķ
+	0x0137: {0x006B, 0x0327}, // ķ
+	0x0139: {0x004C, 0x0301}, // Ĺ
+	0x013A: {0x006C, 0x0301}, // ĺ
+	0x013B: {0x004C, 0x0327}, // Ļ
+	0x013C: {0x006C, 0x0327}, // ļ
+	0x013D: {0x004C, 0x030C}, // Ľ
+	0x013E: {0x006C, 0x030C}, // ľ
+	0x0143: {0x004E, 0x0301}, // Ń
+	0x0144: {0x006E, 0x0301}, // ń
+	0x0145: {0x004E, 0x0327}, // Ņ
+	0x0146: {0x006E, 0x0327}, // ņ
+	0x0147: {0x004E, 0x030C}, // Ň
+	0x0148: {0x006E, 0x030C}, // ň
+	0x014C: {0x004F, 0x0304}, // Ō
+	0x014D: {0x006F, 0x0304}, // ō
+	0x014E: {0x004F, 0x0306}, // Ŏ
+	0x014F: {0x006F, 0x0306}, // ŏ
+	0x0150: {0x004F, 0x030B}, // Ő
+	0x0151: {0x006F, 0x030B}, // ő
+	0x0154: {0x0052, 0x0301}, // Ŕ
+	0x0155: {0x0072, 0x0301}, // ŕ
+	0x0156: {0x0052, 0x0327}, // Ŗ
+	0x0157: {0x0072, 0x0327}, // ŗ
+	0x0158: {0x0052, 0x030C}, // Ř
+	0x0159: {0x0072, 0x030C}, // ř
+	0x015A: {0x0053, 0x0301}, // Ś
+	0x015B: {0x0073, 0x0301}, // ś
+	0x015C: {0x0053, 0x0302}, // Ŝ
+	0x015D: {0x0073, 0x0302}, // ŝ
+	0x015E: {0x0053, 0x0327}, // Ş
+	0x015F: {0x0073, 0x0327}, // ş
+	0x0160: {0x0053, 0x030C}, // Š
+	0x0161: {0x0073, 0x030C}, // š
+	0x0162: {0x0054, 0x0327}, // Ţ
+	0x0163: {0x0074, 0x0327}, // ţ
+	0x0164: {0x0054, 0x030C}, // Ť
+	0x0165: {0x0074, 0x030C}, // ť
+	0x0168: {0x0055, 0x0303}, // Ũ
+	0x0169: {0x0075, 0x0303}, // ũ
+	0x016A: {0x0055, 0x0304}, // Ū
+	0x016B: {0x0075, 0x0304}, // ū
+	0x016C: {0x0055, 0x0306}, // Ŭ
+	0x016D: {0x0075, 0x0306}, // ŭ
+	0x016E: {0x0055, 0x030A}, // Ů
+	0x016F: {0x0075, 0x030A}, // ů
+	0x0170: {0x0055, 0x030B}, // Ű
+	0x0171: {0x0075, 0x030B}, // ű
+	0x0172: {0x0055, 0x0328}, // Ų
+	0x0173: {0x0075, 0x0328}, // ų
+	0x0174: {0x0057, 0x0302}, // Ŵ
+	0x0175: {0x0077, 0x0302}, // ŵ
+	0x0176: {0x0059, 0x0302}, // Ŷ
+	0x0177: {0x0079, 0x0302}, // ŷ
+	0x0178: {0x0059, 0x0308}, // Ÿ
+	0x0179: {0x005A, 0x0301}, // Ź
+	0x017A: {0x007A, 0x0301}, // ź
+	0x017B: {0x005A, 0x0307}, // Ż
+	0x017C: {0x007A, 0x0307}, // ż
+	0x017D: {0x005A, 0x030C}, // Ž
+	0x017E: {0x007A, 0x030C}, // ž
+	0x01A0: {0x004F, 0x031B}, // Ơ
+	0x01A1: {0x006F, 0x031B}, // ơ
+	0x01AF: {0x0055, 0x031B}, // Ư
+	0x01B0: {0x0075, 0x031B}, // ư
+	0x01CD: {0x0041, 0x030C}, // Ǎ
+	0x01CE: {0x0061, 0x030C}, // ǎ
+	0x01CF: {0x0049, 0x030C}, // Ǐ
+	0x01D0: {0x0069, 0x030C}, // ǐ
+	0x01D1: {0x004F, 0x030C}, // Ǒ
+	0x01D2: {0x006F, 0x030C}, // ǒ
+	0x01D3: {0x0055, 0x030C}, // Ǔ
+	0x01D4: {0x0075, 0x030C}, // ǔ
+	0x01D5: {0x00DC, 0x0304}, // Ǖ
+	0x01D6: {0x00FC, 0x0304}, // ǖ
+	0x01D7: {0x00DC, 0x0301}, // Ǘ
+	0x01D8: {0x00FC, 0x0301}, // ǘ
+	0x01D9: {0x00DC, 0x030C}, // Ǚ
+	0x01DA: {0x00FC, 0x030C}, // ǚ
+	0x01DB: {0x00DC, 0x0300}, // Ǜ
+	0x01DC: {0x00FC, 0x0300}, // ǜ
+	0x01DE: {0x00C4, 0x0304}, // Ǟ
+	0x01DF: {0x00E4, 0x0304}, // ǟ
+	0x01E0: {0x0226, 0x0304}, // Ǡ
+	0x01E1: {0x0227, 0x0304}, // ǡ
+	0x01E2: {0x00C6, 0x0304}, // Ǣ
+	0x01E3: {0x00E6, 0x0304}, // ǣ
+	0x01E6: {0x0047, 0x030C}, // Ǧ
+	0x01E7: {0x0067, 0x030C}, // ǧ
+	0x01E8: {0x004B, 0x030C}, // Ǩ
+	0x01E9: {0x006B, 0x030C}, // ǩ
+	0x01EA: {0x004F, 0x0328}, // Ǫ
+	0x01EB: {0x006F, 0x0328}, // ǫ
+	0x01EC: {0x01EA, 0x0304}, // Ǭ
+	0x01ED: {0x01EB, 0x0304}, // ǭ
+	0x01EE: {0x01B7, 0x030C}, // Ǯ
+	0x01EF: {0x0292, 0x030C}, // ǯ
+	0x01F0: {0x006A, 0x030C}, // ǰ
+	0x01F4: {0x0047, 0x0301}, // Ǵ
+	0x01F5: {0x0067, 0x0301}, // ǵ
+	0x01F8: {0x004E, 0x0300}, // Ǹ
+	0x01F9: {0x006E, 0x0300}, // ǹ
+	0x01FA: {0x00C5, 0x0301}, // Ǻ
+	0x01FB: {0x00E5, 0x0301}, // ǻ
+	0x01FC: {0x00C6, 0x0301}, // Ǽ
+	0x01FD: {0x00E6, 0x0301}, // ǽ
+	0x01FE: {0x00D8, 0x0301}, // Ǿ
+	0x01FF: {0x00F8, 0x0301}, // ǿ
+	0x0200: {0x0041, 0x030F}, // Ȁ
+	0x0201: {0x0061, 0x030F}, // ȁ
+	0x0202: {0x0041, 0x0311}, // Ȃ
+	0x0203: {0x0061, 0x0311}, // ȃ
+	0x0204: {0x0045, 0x030F}, // Ȅ
+	0x0205: {0x0065, 0x030F}, // ȅ
+	0x0206: {0x0045, 0x0311}, // Ȇ
+	0x0207: {0x0065, 0x0311}, // ȇ
+	0x0208: {0x0049, 0x030F}, // Ȉ
+	0x0209: {0x0069, 0x030F}, // ȉ
+	0x020A: {0x0049, 0x0311}, // Ȋ
+	0x020B: {0x0069, 0x0311}, // ȋ
+	0x020C: {0x004F, 0x030F}, // Ȍ
+	0x020D: {0x006F, 0x030F}, // ȍ
+	0x020E: {0x004F, 0x0311}, // Ȏ
+	0x020F: {0x006F, 0x0311}, // ȏ
+	0x0210: {0x0052, 0x030F}, // Ȑ
+	0x0211: {0x0072, 0x030F}, // ȑ
+	0x0212: {0x0052, 0x0311}, // Ȓ
+	0x0213: {0x0072, 0x0311}, // ȓ
+	0x0214: {0x0055, 0x030F}, // Ȕ
+	0x0215: {0x0075, 0x030F}, // ȕ
+	0x0216: {0x0055, 0x0311}, // Ȗ
+	0x0217: {0x0075, 0x0311}, // ȗ
+	0x0218: {0x0053, 0x0326}, // Ș
+	0x0219: {0x0073, 0x0326}, // ș
+	0x021A: {0x0054, 0x0326}, // Ț
+	0x021B: {0x0074, 0x0326}, // ț
+	0x021E: {0x0048, 0x030C}, // Ȟ
+	0x021F: {0x0068, 0x030C}, // ȟ
+	0x0226: {0x0041, 0x0307}, // Ȧ
+	0x0227: {0x0061, 0x0307}, // ȧ
+	0x0228: {0x0045, 0x0327}, // Ȩ
+	0x0229: {0x0065, 0x0327}, // ȩ
+	0x022A: {0x00D6, 0x0304}, // Ȫ
+	0x022B: {0x00F6, 0x0304}, // ȫ
+	0x022C: {0x00D5, 0x0304}, // Ȭ
+	0x022D: {0x00F5, 0x0304}, // ȭ
+	0x022E: {0x004F, 0x0307}, // Ȯ
+	0x022F: {0x006F, 0x0307}, // ȯ
+	0x0230: {0x022E, 0x0304}, // Ȱ
+	0x0231: {0x022F, 0x0304}, // ȱ
+	0x0232: {0x0059, 0x0304}, // Ȳ
+	0x0233: {0x0079, 0x0304}, // ȳ
+	0x1E00: {0x0041, 0x0325}, // Ḁ
+	0x1E01: {0x0061, 0x0325}, // ḁ
+	0x1E02: {0x0042, 0x0307}, // Ḃ
+	0x1E03: {0x0062, 0x0307}, // ḃ
+	0x1E04: {0x0042, 0x0323}, // Ḅ
+	0x1E05: {0x0062, 0x0323}, // ḅ
+	0x1E06: {0x0042, 0x0331}, // Ḇ
+	0x1E07: {0x0062, 0x0331}, // ḇ
+	0x1E08: {0x00C7, 0x0301}, // Ḉ
+	0x1E09: {0x00E7, 0x0301}, // ḉ
+	0x1E0A: {0x0044, 0x0307}, // Ḋ
+	0x1E0B: {0x0064, 0x0307}, // ḋ
+	0x1E0C: {0x0044, 0x0323}, // Ḍ
+	0x1E0D: {0x0064, 0x0323}, // ḍ
+	0x1E0E: {0x0044, 0x0331}, // Ḏ
+	0x1E0F: {0x0064, 0x0331}, // ḏ
+	0x1E10: {0x0044, 0x0327}, // Ḑ
+	0x1E11: {0x0064, 0x0327}, // ḑ
+	0x1E12: {0x0044, 0x032D}, // Ḓ
+	0x1E13: {0x0064, 0x032D}, // ḓ
+	0x1E14: {0x0112, 0x0300}, // Ḕ
+	0x1E15: {0x0113, 0x0300}, // ḕ
+	0x1E16: {0x0112, 0x0301}, // Ḗ
+	0x1E17: {0x0113, 0x0301}, // ḗ
+	0x1E18: {0x0045, 0x032D}, // Ḙ
+	0x1E19: {0x0065, 0x032D}, // ḙ
+	0x1E1A: {0x0045, 0x0330}, // Ḛ
+	0x1E1B: {0x0065, 0x0330}, // ḛ
+	0x1E1C: {0x0228, 0x0306}, // Ḝ
+	0x1E1D: {0x0229, 0x0306}, // ḝ
+	0x1E1E: {0x0046, 0x0307}, // Ḟ
+	0x1E1F: {0x0066, 0x0307}, // ḟ
+	0x1E20: {0x0047, 0x0304}, // Ḡ
+	0x1E21: {0x0067, 0x0304}, // ḡ
+	0x1E22: {0x0048, 0x0307}, // Ḣ
+	0x1E23: {0x0068, 0x0307}, // ḣ
+	0x1E24: {0x0048, 0x0323}, // Ḥ
+	0x1E25: {0x0068, 0x0323}, // ḥ
+	0x1E26: {0x0048, 0x0308}, // Ḧ
+	0x1E27: {0x0068, 0x0308}, // ḧ
+	0x1E28: {0x0048, 0x0327}, // Ḩ
+	0x1E29: {0x0068, 0x0327}, // ḩ
+	0x1E2A: {0x0048, 0x032E}, // Ḫ
+	0x1E2B: {0x0068, 0x032E}, // ḫ
+	0x1E2C: {0x0049, 0x0330}, // Ḭ
+	0x1E2D: {0x0069, 0x0330}, // ḭ
+	0x1E2E: {0x00CF, 0x0301}, // Ḯ
+	0x1E2F: {0x00EF, 0x0301}, // ḯ
+	0x1E30: {0x004B, 0x0301}, // Ḱ
+	0x1E31: {0x006B, 0x0301}, // ḱ
+	0x1E32: {0x004B, 0x0323}, // Ḳ
+	0x1E33: {0x006B, 0x0323}, // ḳ
+	0x1E34: {0x004B, 0x0331}, // Ḵ
+	0x1E35: {0x006B, 0x0331}, // ḵ
+	0x1E36: {0x004C, 0x0323}, // Ḷ
+	0x1E37: {0x006C, 0x0323}, // ḷ
+	0x1E38: {0x1E36, 0x0304}, // Ḹ
+	0x1E39: {0x1E37, 0x0304}, // ḹ
+	0x1E3A: {0x004C, 0x0331}, // Ḻ
+	0x1E3B: {0x006C, 0x0331}, // ḻ
+	0x1E3C: {0x004C, 0x032D}, // Ḽ
+	0x1E3D: {0x006C, 0x032D}, // ḽ
+	0x1E3E: {0x004D, 0x0301}, // Ḿ
+	0x1E3F: {0x006D, 0x0301}, // ḿ
+	0x1E40: {0x004D, 0x0307}, // Ṁ
+	0x1E41: {0x006D, 0x0307}, // ṁ
+	0x1E42: {0x004D, 0x0323}, // Ṃ
+	0x1E43: {0x006D, 0x0323}, // ṃ
+	0x1E44: {0x004E, 0x0307}, // Ṅ
+	0x1E45: {0x006E, 0x0307}, // ṅ
+	0x1E46: {0x004E, 0x0323}, // Ṇ
+	0x1E47: {0x006E, 0x0323}, // ṇ
+	0x1E48: {0x004E, 0x0331}, // Ṉ
+	0x1E49: {0x006E, 0x0331}, // ṉ
+	0x1E4A: {0x004E, 0x032D}, // Ṋ
+	0x1E4B: {0x006E, 0x032D}, // ṋ
+	0x1E4C: {0x00D5, 0x0301}, // Ṍ
+	0x1E4D: {0x00F5, 0x0301}, // ṍ
+	0x1E4E: {0x00D5, 0x0308}, // Ṏ
+	0x1E4F: {0x00F5, 0x0308}, // ṏ
+	0x1E50: {0x014C, 0x0300}, // Ṑ
+	0x1E51: {0x014D, 0x0300}, // ṑ
+	0x1E52: {0x014C, 0x0301}, // Ṓ
+	0x1E53: {0x014D, 0x0301}, // ṓ
+	0x1E54: {0x0050, 0x0301}, // Ṕ
+	0x1E55: {0x0070, 0x0301}, // ṕ
+	0x1E56: {0x0050, 0x0307}, // Ṗ
+	0x1E57: {0x0070, 0x0307}, // ṗ
+	0x1E58: {0x0052, 0x0307}, // Ṙ
+	0x1E59: {0x0072, 0x0307}, // ṙ
+	0x1E5A: {0x0052, 0x0323}, // Ṛ
+	0x1E5B: {0x0072, 0x0323}, // ṛ
+	0x1E5C: {0x1E5A, 0x0304}, // Ṝ
+	0x1E5D: {0x1E5B, 0x0304}, // ṝ
+	0x1E5E: {0x0052, 0x0331}, // Ṟ
+	0x1E5F: {0x0072, 0x0331}, // ṟ
+	0x1E60: {0x0053, 0x0307}, // Ṡ
+	0x1E61: {0x0073, 0x0307}, // ṡ
+	0x1E62: {0x0053, 0x0323}, // Ṣ
+	0x1E63: {0x0073, 0x0323}, // ṣ
+	0x1E64: {0x015A, 0x0307}, // Ṥ
+	0x1E65: {0x015B, 0x0307}, // ṥ
+	0x1E66: {0x0160, 0x0307}, // Ṧ
+	0x1E67: {0x0161, 0x0307}, // ṧ
+	0x1E68: {0x1E62, 0x0307}, // Ṩ
+	0x1E69: {0x1E63, 0x0307}, // ṩ
+	0x1E6A: {0x0054, 0x0307}, // Ṫ
+	0x1E6B: {0x0074, 0x0307}, // ṫ
+	0x1E6C: {0x0054, 0x0323}, // Ṭ
+	0x1E6D: {0x0074, 0x0323}, // ṭ
+	0x1E6E: {0x0054, 0x0331}, // Ṯ
+	0x1E6F: {0x0074, 0x0331}, // ṯ
+	0x1E70: {0x0054, 0x032D}, // Ṱ
+	0x1E71: {0x0074, 0x032D}, // ṱ
+	0x1E72: {0x0055, 0x0324}, // Ṳ
+	0x1E73: {0x0075, 0x0324}, // ṳ
+	0x1E74: {0x0055, 0x0330}, // Ṵ
+	0x1E75: {0x0075, 0x0330}, // ṵ
+	0x1E76: {0x0055, 0x032D}, // Ṷ
+	0x1E77: {0x0075, 0x032D}, // ṷ
+	0x1E78: {0x0168, 0x0301}, // Ṹ
+	0x1E79: {0x0169, 0x0301}, // ṹ
+	0x1E7A: {0x016A, 0x0308}, // Ṻ
+	0x1E7B: {0x016B, 0x0308}, // ṻ
+	0x1E7C: {0x0056, 0x0303}, // Ṽ
+	0x1E7D: {0x0076, 0x0303}, // ṽ
+	0x1E7E: {0x0056, 0x0323}, // Ṿ
+	0x1E7F: {0x0076, 0x0323}, // ṿ
+	0x1E80: {0x0057, 0x0300}, // Ẁ
+	0x1E81: {0x0077, 0x0300}, // ẁ
+	0x1E82: {0x0057, 0x0301}, // Ẃ
+	0x1E83: {0x0077, 0x0301}, // ẃ
+	0x1E84: {0x0057, 0x0308}, // Ẅ
+	0x1E85: {0x0077, 0x0308}, // ẅ
+	0x1E86: {0x0057, 0x0307}, // Ẇ
+	0x1E87: {0x0077, 0x0307}, // ẇ
+	0x1E88: {0x0057, 0x0323}, // Ẉ
+	0x1E89: {0x0077, 0x0323}, // ẉ
+	0x1E8A: {0x0058, 0x0307}, // Ẋ
+	0x1E8B: {0x0078, 0x0307}, // ẋ
+	0x1E8C: {0x0058, 0x0308}, // Ẍ
+	0x1E8D: {0x0078, 0x0308}, // ẍ
+	0x1E8E: {0x0059, 0x0307}, // Ẏ
+	0x1E8F: {0x0079, 0x0307}, // ẏ
+	0x1E90: {0x005A, 0x0302}, // Ẑ
+	0x1E91: {0x007A, 0x0302}, // ẑ
+	0x1E92: {0x005A, 0x0323}, // Ẓ
+	0x1E93: {0x007A, 0x0323}, // ẓ
+	0x1E94: {0x005A, 0x0331}, // Ẕ
+	0x1E95: {0x007A, 0x0331}, // ẕ
+	0x1E96: {0x0068, 0x0331}, // ẖ
+	0x1E97: {0x0074, 0x0308}, // ẗ
+	0x1E98: {0x0077, 0x030A}, // ẘ
+	0x1E99: {0x0079, 0x030A}, // ẙ
+	0x1E9B: {0x017F, 0x0307}, // ẛ
+	0x1EA0: {0x0041, 0x0323}, // Ạ
+	0x1EA1: {0x0061, 0x0323}, // ạ
+	0x1EA2: {0x0041, 0x0309}, // Ả
+	0x1EA3: {0x0061, 0x0309}, // ả
+	0x1EA4: {0x00C2, 0x0301}, // Ấ
+	0x1EA5: {0x00E2, 0x0301}, // ấ
+	0x1EA6: {0x00C2, 0x0300}, // Ầ
+	0x1EA7: {0x00E2, 0x0300}, // ầ
+	0x1EA8: {0x00C2, 0x0309}, // Ẩ
+	0x1EA9: {0x00E2, 0x0309}, // ẩ
+	0x1EAA: {0x00C2, 0x0303}, // Ẫ
+	0x1EAB: {0x00E2, 0x0303}, // ẫ
+	0x1EAC: {0x1EA0, 0x0302}, // Ậ
+	0x1EAD: {0x1EA1, 0x0302}, // ậ
+	0x1EAE: {0x0102, 0x0301}, // Ắ
+	0x1EAF: {0x0103, 0x0301}, // ắ
+	0x1EB0: {0x0102, 0x0300}, // Ằ
+	0x1EB1: {0x0103, 0x0300}, // ằ
+	0x1EB2: {0x0102, 0x0309}, // Ẳ
+	0x1EB3: {0x0103, 0x0309}, // ẳ
+	0x1EB4: {0x0102, 0x0303}, // Ẵ
+	0x1EB5: {0x0103, 0x0303}, // ẵ
+	0x1EB6: {0x1EA0, 0x0306}, // Ặ
+	0x1EB7: {0x1EA1, 0x0306}, // ặ
+	0x1EB8: {0x0045, 0x0323}, // Ẹ
+	0x1EB9: {0x0065, 0x0323}, // ẹ
+	0x1EBA: {0x0045, 0x0309}, // Ẻ
+	0x1EBB: {0x0065, 0x0309}, // ẻ
+	0x1EBC: {0x0045, 0x0303}, // Ẽ
+	0x1EBD: {0x0065, 0x0303}, // ẽ
+	0x1EBE: {0x00CA, 0x0301}, // Ế
+	0x1EBF: {0x00EA, 0x0301}, // ế
+	0x1EC0: {0x00CA, 0x0300}, // Ề
+	0x1EC1: {0x00EA, 0x0300}, // ề
+	0x1EC2: {0x00CA, 0x0309}, // Ể
+	0x1EC3: {0x00EA, 0x0309}, // ể
+	0x1EC4: {0x00CA, 0x0303}, // Ễ
+	0x1EC5: {0x00EA, 0x0303}, // ễ
+	0x1EC6: {0x1EB8, 0x0302}, // Ệ
+	0x1EC7: {0x1EB9, 0x0302}, // ệ
+	0x1EC8: {0x0049, 0x0309}, // Ỉ
+	0x1EC9: {0x0069, 0x0309}, // ỉ
+	0x1ECA: {0x0049, 0x0323}, // Ị
+	0x1ECB: {0x0069, 0x0323}, // ị
+	0x1ECC: {0x004F, 0x0323}, // Ọ
+	0x1ECD: {0x006F, 0x0323}, // ọ
+	0x1ECE: {0x004F, 0x0309}, // Ỏ
+	0x1ECF: {0x006F, 0x0309}, // ỏ
+	0x1ED0: {0x00D4, 0x0301}, // Ố
+	0x1ED1: {0x00F4, 0x0301}, // ố
+	0x1ED2: {0x00D4, 0x0300}, // Ồ
+	0x1ED3: {0x00F4, 0x0300}, // ồ
+	0x1ED4: {0x00D4, 0x0309}, // Ổ
+	0x1ED5: {0x00F4, 0x0309}, // ổ
+	0x1ED6: {0x00D4, 0x0303}, // Ỗ
+	0x1ED7: {0x00F4, 0x0303}, // ỗ
+	0x1ED8: {0x1ECC, 0x0302}, // Ộ
+	0x1ED9: {0x1ECD, 0x0302}, // ộ
+	0x1EDA: {0x01A0, 0x0301}, // Ớ
+	0x1EDB: {0x01A1, 0x0301}, // ớ
+	0x1EDC: {0x01A0, 0x0300}, // Ờ
+	0x1EDD: {0x01A1, 0x0300}, // ờ
+	0x1EDE: {0x01A0, 0x0309}, // Ở
+	0x1EDF: {0x01A1, 0x0309}, // ở
+	0x1EE0: {0x01A0, 0x0303}, // Ỡ
+	0x1EE1: {0x01A1, 0x0303}, // ỡ
+	0x1EE2: {0x01A0, 0x0323}, // Ợ
+	0x1EE3: {0x01A1, 0x0323}, // ợ
+	0x1EE4: {0x0055, 0x0323}, // Ụ
+	0x1EE5: {0x0075, 0x0323}, // ụ
+	0x1EE6: {0x0055, 0x0309}, // Ủ
+	0x1EE7: {0x0075, 0x0309}, // ủ
+	0x1EE8: {0x01AF, 0x0301}, // Ứ
+	0x1EE9: {0x01B0, 0x0301}, // ứ
+	0x1EEA: {0x01AF, 0x0300}, // Ừ
+	0x1EEB: {0x01B0, 0x0300}, // ừ
+	0x1EEC: {0x01AF, 0x0309}, // Ử
+	0x1EED: {0x01B0, 0x0309}, // ử
+	0x1EEE: {0x01AF, 0x0303}, // Ữ
+	0x1EEF: {0x01B0, 0x0303}, // ữ
+	0x1EF0: {0x01AF, 0x0323}, // Ự
+	0x1EF1: {0x01B0, 0x0323}, // ự
+	0x1EF2: {0x0059, 0x0300}, // Ỳ
+	0x1EF3: {0x0079, 0x0300}, // ỳ
+	0x1EF4: {0x0059, 0x0323}, // Ỵ
+	0x1EF5: {0x0079, 0x0323}, // ỵ
+	0x1EF6: {0x0059, 0x0309}, // Ỷ
+	0x1EF7: {0x0079, 0x0309}, // ỷ
+	0x1EF8: {0x0059, 0x0303}, // Ỹ
+	0x1EF9: {0x0079, 0x0303}, // ỹ
+}
+
+// combiningClasses 是组合附加符号的规范组合类（ccc），用于 NFD 的规范排序。
+var combiningClasses = map[rune]uint8{
+	0x0300: 230,
+	0x0301: 230,
+	0x0302: 230,
+	0x0303: 230,
+	0x0304: 230,
+	0x0305: 230,
+	0x0306: 230,
+	0x0307: 230,
+	0x0308: 230,
+	0x0309: 230,
+	0x030A: 230,
+	0x030B: 230,
+	0x030C: 230,
+	0x030D: 230,
+	0x030E: 230,
+	0x030F: 230,
+	0x0310: 230,
+	0x0311: 230,
+	0x0312: 230,
+	0x0313: 230,
+	0x0314: 230,
+	0x0315: 232,
+	0x0316: 220,
+	0x0317: 220,
+	0x0318: 220,
+	0x0319: 220,
+	0x031A: 232,
+	0x031B: 216,
+	0x031C: 220,
+	0x031D: 220,
+	0x031E: 220,
+	0x031F: 220,
+	0x0320: 220,
+	0x0321: 202,
+	0x0322: 202,
+	0x0323: 220,
+	0x0324: 220,
+	0x0325: 220,
+	0x0326: 220,
+	0x0327: 202,
+	0x0328: 202,
+	0x0329: 220,
+	0x032A: 220,
+	0x032B: 220,
+	0x032C: 220,
+	0x032D: 220,
+	0x032E: 220,
+	0x032F: 220,
+	0x0330: 220,
+	0x0331: 220,
+	0x0332: 220,
+	0x0333: 220,
+	0x0334: 1,
+	0x0335: 1,
+	0x0336: 1,
+	0x0337: 1,
+	0x0338: 1,
+	0x0339: 220,
+	0x033A: 220,
+	0x033B: 220,
+	0x033C: 220,
+	0x033D: 230,
+	0x033E: 230,
+	0x033F: 230,
+	0x0340: 230,
+	0x0341: 230,
+	0x0342: 230,
+	0x0343: 230,
+	0x0344: 230,
+	0x0345: 240,
+	0x0346: 230,
+	0x0347: 220,
+	0x0348: 220,
+	0x0349: 220,
+	0x034A: 230,
+	0x034B: 230,
+	0x034C: 230,
+	0x034D: 220,
+	0x034E: 220,
+	0x0350: 230,
+	0x0351: 230,
+	0x0352: 230,
+	0x0353: 220,
+	0x0354: 220,
+	0x0355: 220,
+	0x0356: 220,
+	0x0357: 230,
+	0x0358: 232,
+	0x0359: 220,
+	0x035A: 220,
+	0x035B: 230,
+	0x035C: 233,
+	0x035D: 234,
+	0x035E: 234,
+	0x035F: 233,
+	0x0360: 234,
+	0x0361: 234,
+	0x0362: 233,
+	0x0363: 230,
+	0x0364: 230,
+	0x0365: 230,
+	0x0366: 230,
+	0x0367: 230,
+	0x0368: 230,
+	0x0369: 230,
+	0x036A: 230,
+	0x036B: 230,
+	0x036C: 230,
+	0x036D: 230,
+	0x036E: 230,
+	0x036F: 230,
+}
+
+// fullCaseFoldings 是 CaseFolding.txt 中状态为 F 的完整折叠映射，
+// 其余字符使用简单折叠即可。
+var fullCaseFoldings = map[rune]string{
+	0x00DF: "ss",                 // ß
+	0x0130: "i\u0307",            // İ
+	0x0149: "\u02BCn",            // ŉ
+	0x01F0: "j\u030C",            // ǰ
+	0x0390: "\u03B9\u0308\u0301", // ΐ
+	0x03B0: "\u03C5\u0308\u0301", // ΰ
+	0x0587: "\u0565\u0582",       // և
+	0x1E96: "h\u0331",            // ẖ
+	0x1E97: "t\u0308",            // ẗ
+	0x1E98: "w\u030A",            // ẘ
+	0x1E99: "y\u030A",            // ẙ
+	0x1E9A: "a\u02BE",            // ẚ
+	0x1E9E: "ss",                 // ẞ
+	0x1F50: "\u03C5\u0313",       // ὐ
+	0x1F52: "\u03C5\u0313\u0300", // ὒ
+	0x1F54: "\u03C5\u0313\u0301", // ὔ
+	0x1F56: "\u03C5\u0313\u0342", // ὖ
+	0x1F80: "\u1F00\u03B9",       // ᾀ
+	0x1F81: "\u1F01\u03B9",       // ᾁ
+	0x1F82: "\u1F02\u03B9",       // ᾂ
+	0x1F83: "\u1F03\u03B9",       // ᾃ
+	0x1F84: "\u1F04\u03B9",       // ᾄ
+	0x1F85: "\u1F05\u03B9",       // ᾅ
+	0x1F86: "\u1F06\u03B9",       // ᾆ
+	0x1F87: "\u1F07\u03B9",       // ᾇ
+	0x1F88: "\u1F00\u03B9",       // ᾈ
+	0x1F89: "\u1F01\u03B9",       // ᾉ
+	0x1F8A: "\u1F02\u03B9",       // ᾊ
+	0x1F8B: "\u1F03\u03B9",       // ᾋ
+	0x1F8C: "\u1F04\u03B9",       // ᾌ
+	0x1F8D: "\u1F05\u03B9",       // ᾍ
+	0x1F8E: "\u1F06\u03B9",       // ᾎ
+	0x1F8F: "\u1F07\u03B9",       // ᾏ
+	0x1F90: "\u1F20\u03B9",       // ᾐ
+	0x1F91: "\u1F21\u03B9",       // ᾑ
+	0x1F92: "\u1F22\u03B9",       // ᾒ
+	0x1F93: "\u1F23\u03B9",       // ᾓ
+	0x1F94: "\u1F24\u03B9",       // ᾔ
+	0x1F95: "\u1F25\u03B9",       // ᾕ
+	0x1F96: "\u1F26\u03B9",       // ᾖ
+	0x1F97: "\u1F27\u03B9",       // ᾗ
+	0x1F98: "\u1F20\u03B9",       // ᾘ
+	0x1F99: "\u1F21\u03B9",       // ᾙ
+	0x1F9A: "\u1F22\u03B9",       // ᾚ
+	0x1F9B: "\u1F23\u03B9",       // ᾛ
+	0x1F9C: "\u1F24\u03B9",       // ᾜ
+	0x1F9D: "\u1F25\u03B9",       // ᾝ
+	0x1F9E: "\u1F26\u03B9",       // ᾞ
+	0x1F9F: "\u1F27\u03B9",       // ᾟ
+	0x1FA0: "\u1F60\u03B9",       // ᾠ
+	0x1FA1: "\u1F61\u03B9",       // ᾡ
+	0x1FA2: "\u1F62\u03B9",       // ᾢ
+	0x1FA3: "\u1F63\u03B9",       // ᾣ
+	0x1FA4: "\u1F64\u03B9",       // ᾤ
+	0x1FA5: "\u1F65\u03B9",       // ᾥ
+	0x1FA6: "\u1F66\u03B9",       // ᾦ
+	0x1FA7: "\u1F67\u03B9",       // ᾧ
+	0x1FA8: "\u1F60\u03B9",       // ᾨ
+	0x1FA9: "\u1F61\u03B9",       // ᾩ
+	0x1FAA: "\u1F62\u03B9",       // ᾪ
+	0x1FAB: "\u1F63\u03B9",       // ᾫ
+	0x1FAC: "\u1F64\u03B9",       // ᾬ
+	0x1FAD: "\u1F65\u03B9",       // ᾭ
+	0x1FAE: "\u1F66\u03B9",       // ᾮ
+	0x1FAF: "\u1F67\u03B9",       // ᾯ
+	0x1FB2: "\u1F70\u03B9",       // ᾲ
+	0x1FB3: "\u03B1\u03B9",       // ᾳ
+	0x1FB4: "\u03AC\u03B9",       // ᾴ
+	0x1FB6: "\u03B1\u0342",       // ᾶ
+	0x1FB7: "\u03B1\u0342\u03B9", // ᾷ
+	0x1FBC: "\u03B1\u03B9",       // ᾼ
+	0x1FC2: "\u1F74\u03B9",       // ῂ
+	0x1FC3: "\u03B7\u03B9",       // ῃ
+	0x1FC4: "\u03AE\u03B9",       // ῄ
+	0x1FC6: "\u03B7\u0342",       // ῆ
+	0x1FC7: "\u03B7\u0342\u03B9", // ῇ
+	0x1FCC: "\u03B7\u03B9",       // ῌ
+	0x1FD2: "\u03B9\u0308\u0300", // ῒ
+	0x1FD3: "\u03B9\u0308\u0301", // ΐ
+	0x1FD6: "\u03B9\u0342",       // ῖ
+	0x1FD7: "\u03B9\u0308\u0342", // ῗ
+	0x1FE2: "\u03C5\u0308\u0300", // ῢ
+	0x1FE3: "\u03C5\u0308\u0301", // ΰ
+	0x1FE4: "\u03C1\u0313",       // ῤ
+	0x1FE6: "\u03C5\u0342",       // ῦ
+	0x1FE7: "\u03C5\u0308\u0342", // ῧ
+	0x1FF2: "\u1F7C\u03B9",       // ῲ
+	0x1FF3: "\u03C9\u03B9",       // ῳ
+	0x1FF4: "\u03CE\u03B9",       // ῴ
+	0x1FF6: "\u03C9\u0342",       // ῶ
+	0x1FF7: "\u03C9\u0342\u03B9", // ῷ
+	0x1FFC: "\u03C9\u03B9",       // ῼ
+	0xFB00: "ff",                 // ﬀ
+	0xFB01: "fi",                 // ﬁ
+	0xFB02: "fl",                 // ﬂ
+	0xFB03: "ffi",                // ﬃ
+	0xFB04: "ffl",                // ﬄ
+	0xFB05: "st",                 // ﬅ
+	0xFB06: "st",                 // ﬆ
+	0xFB13: "\u0574\u0576",       // ﬓ
+	0xFB14: "\u0574\u0565",       // ﬔ
+	0xFB15: "\u0574\u056B",       // ﬕ
+	0xFB16: "\u057E\u0576",       // ﬖ
+	0xFB17: "\u0574\u056D",       // ﬗ
+}
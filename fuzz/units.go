@@ -0,0 +1,140 @@
+package fuzz
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"go-libs-learning-kit/internal/wordbreak"
+)
+
+// Tokenizer 把文本切分为回文比较的单元。
+type Tokenizer func(s string) []string
+
+// UnitOptions 控制 IsPalindromeUnits 的切分方式和单元内部的比较规则。
+type UnitOptions struct {
+	// Tokenizer 为 nil 时按空白字符切分
+	Tokenizer Tokenizer
+	// PalindromeOptions 用于规范化每个单元，规范化后为空的单元被忽略
+	PalindromeOptions
+}
+
+// IsPalindromeUnits 以单词、行等为单位判断是否是回文，
+// 例如 "Fall leaves after leaves fall" 按单词比较是回文。
+func IsPalindromeUnits(s string, opts UnitOptions) bool {
+	tokenize := opts.Tokenizer
+	if tokenize == nil {
+		tokenize = strings.Fields
+	}
+
+	var units []string
+	for _, token := range tokenize(s) {
+		units = appendUnit(units, token, opts.PalindromeOptions)
+	}
+	return unitsPalindrome(units)
+}
+
+// IsPalindromeLines 以行为单位判断 r 的内容是否是回文，结果与使用 SplitLines 的 IsPalindromeUnits 相同，
+// 但逐行读取，不需要先把整个文件读成字符串，内存中只保存每行规范化后的结果。
+func IsPalindromeLines(r io.Reader, opts PalindromeOptions) (bool, error) {
+	br := bufio.NewReader(r)
+	var units []string
+	for {
+		line, err := br.ReadString('\n')
+		line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+		units = appendUnit(units, line, opts)
+		if err == io.EOF {
+			return unitsPalindrome(units), nil
+		}
+		if err != nil {
+			return false, err
+		}
+	}
+}
+
+// appendUnit 把 token 规范化后追加到 units，规范化后为空时忽略
+func appendUnit(units []string, token string, opts PalindromeOptions) []string {
+	if rs, _ := normalizeUnits(token, opts); len(rs) > 0 {
+		units = append(units, string(rs))
+	}
+	return units
+}
+
+func unitsPalindrome(units []string) bool {
+	for i, j := 0, len(units)-1; i < j; i, j = i+1, j-1 {
+		if units[i] != units[j] {
+			return false
+		}
+	}
+	return true
+}
+
+// SplitLines 按行切分，兼容 \r\n 换行。
+func SplitLines(s string) []string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+	return lines
+}
+
+// SplitWords 按 Unicode 单词边界（UAX #29 的子集，与 byte 包 WholeWord 使用的规则相同）切分，
+// 只返回含有字母或数字的片段，丢弃空白和标点。
+// 字母与数字连续组成一个单词，片假名连续组成一个单词，汉字和平假名每个字符单独成词，
+// 单词内部的撇号、句点（如 don't、3.14）不会断开。
+func SplitWords(s string) []string {
+	return splitWords(s, false)
+}
+
+// SplitCJK 与 SplitWords 相同，但汉字、假名和谚文每个字符都单独成词。
+func SplitCJK(s string) []string {
+	return splitWords(s, true)
+}
+
+func splitWords(s string, splitCJK bool) []string {
+	b := []byte(s)
+	var words []string
+	start := 0
+	for i := 0; i < len(s); {
+		_, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		if wordbreak.IsBoundary(b, i) {
+			words = appendWord(words, s[start:i], splitCJK)
+			start = i
+		}
+	}
+	return words
+}
+
+// appendWord 在片段 seg 含有字母或数字时把它追加到 words，splitCJK 为 true 时先拆开其中的 CJK 字符，
+// 附加符号跟随前一个字符。
+func appendWord(words []string, seg string, splitCJK bool) []string {
+	if strings.IndexFunc(seg, isWordRune) < 0 {
+		return words
+	}
+	if !splitCJK {
+		return append(words, seg)
+	}
+	start, prevCJK := 0, false
+	for i, r := range seg {
+		if isMark(r) {
+			continue
+		}
+		if i > start && (prevCJK || isCJK(r)) {
+			words = append(words, seg[start:i])
+			start = i
+		}
+		prevCJK = isCJK(r)
+	}
+	return append(words, seg[start:])
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Pc, r)
+}
+
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) || r == 'ー'
+}
@@ -0,0 +1,106 @@
+package fuzz
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestSplitWords(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		splitCJK bool
+		want     []string
+	}{
+		{"Punctuation", "Hello, world!", false, []string{"Hello", "world"}},
+		{"Apostrophe", "don't stop", false, []string{"don't", "stop"}},
+		{"Trailing Apostrophe", "dogs' bones", false, []string{"dogs", "bones"}},
+		{"Decimal", "pi is 3.14, e is 2,718", false, []string{"pi", "is", "3.14", "e", "is", "2,718"}},
+		{"Letters And Digits", "abc123 x", false, []string{"abc123", "x"}},
+		{"Combining Mark", "cafe\u0301 ok", false, []string{"cafe\u0301", "ok"}},
+		{"Han", "我爱你", false, []string{"我", "爱", "你"}},
+		{"Katakana Run", "テストです", false, []string{"テスト", "で", "す"}},
+		{"Katakana Split", "テストです", true, []string{"テ", "ス", "ト", "で", "す"}},
+		{"Hangul Word", "안녕 세상", false, []string{"안녕", "세상"}},
+		{"Hangul Split", "안녕", true, []string{"안", "녕"}},
+		{"Mixed Script", "Go语言", false, []string{"Go", "语", "言"}},
+		{"Soft Hyphen", "co\u00adop", false, []string{"co\u00adop"}},
+		{"Katakana Connector", "テスト_case", false, []string{"テスト_case"}},
+		{"Katakana Connector Split", "テスト_case", true, []string{"テ", "ス", "ト", "_case"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			split := SplitWords
+			if tt.splitCJK {
+				split = SplitCJK
+			}
+			if got := split(tt.input); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("split(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitLines(t *testing.T) {
+	got := SplitLines("a\r\nb\n\nc")
+	want := []string{"a", "b", "", "c"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SplitLines = %q, want %q", got, want)
+	}
+}
+
+func TestIsPalindromeLines(t *testing.T) {
+	for _, input := range []string{
+		"alpha\nbeta\r\ngamma\nbeta\nalpha\n",
+		"alpha\nbeta\nalpha beta",
+		"One\n\n...\none",
+		"",
+	} {
+		want := IsPalindromeUnits(input, UnitOptions{Tokenizer: SplitLines})
+		got, err := IsPalindromeLines(iotest.HalfReader(strings.NewReader(input)), PalindromeOptions{})
+		if got != want || err != nil {
+			t.Errorf("IsPalindromeLines(%q) = %v, %v; want %v", input, got, err, want)
+		}
+	}
+
+	errBoom := errors.New("boom")
+	r := io.MultiReader(strings.NewReader("a\nb\na\n"), iotest.ErrReader(errBoom))
+	if got, err := IsPalindromeLines(r, PalindromeOptions{}); got || !errors.Is(err, errBoom) {
+		t.Errorf("IsPalindromeLines with read error = %v, %v; want false, %v", got, err, errBoom)
+	}
+}
+
+func TestIsPalindromeUnits(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		opts  UnitOptions
+		want  bool
+	}{
+		{"Whitespace Words", "fall leaves after leaves fall", UnitOptions{}, true},
+		{"Case And Punctuation", "First Ladies rule the State, and state the rule: ladies first!", UnitOptions{}, true},
+		{"Word Order Matters", "one two three", UnitOptions{}, false},
+		{"Word Tokenizer", "You can cage a swallow, can't you, but you can't swallow a cage, can you?",
+			UnitOptions{Tokenizer: SplitWords}, true},
+		{"Lines", "alpha\nbeta\r\ngamma\nbeta\nalpha\n", UnitOptions{Tokenizer: SplitLines}, true},
+		{"Lines Not Palindrome", "alpha\nbeta\nalpha beta", UnitOptions{Tokenizer: SplitLines}, false},
+		{"CJK Per Character", "我爱你，你爱我", UnitOptions{Tokenizer: SplitCJK}, true},
+		{"Katakana Word", "テスト は テスト", UnitOptions{Tokenizer: SplitWords}, true},
+		{"Katakana Characters", "テスト", UnitOptions{Tokenizer: SplitCJK}, false},
+		{"Digits Included", "1 2 1", UnitOptions{PalindromeOptions: PalindromeOptions{IncludeDigits: true}}, true},
+		{"Digits Included Mismatch", "1 2 3", UnitOptions{PalindromeOptions: PalindromeOptions{IncludeDigits: true}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsPalindromeUnits(tt.input, tt.opts); got != tt.want {
+				t.Errorf("IsPalindromeUnits(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
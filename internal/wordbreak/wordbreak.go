@@ -0,0 +1,163 @@
+// Package wordbreak 实现 UAX #29 单词边界规则的一个子集，供 byte 的 WholeWord 匹配和 fuzz 的单词切分共用，
+// 保证两处对单词的理解一致。
+package wordbreak
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// wordClass 是 UAX #29 中 Word_Break 属性的一个子集，
+// 省略了 Hebrew_Letter、Regional_Indicator 和 Extended_Pictographic 相关的规则。
+type wordClass int
+
+const (
+	wbNone wordClass = iota // 文本开头或结尾
+	wbOther
+	wbNewline // CR、LF 和其他换行符
+	wbCR
+	wbLF
+	wbExtend // 组合符号、Format 和 ZWJ
+	wbALetter
+	wbNumeric
+	wbKatakana
+	wbExtendNumLet
+	wbMidLetter
+	wbMidNum
+	wbMidNumLet
+	wbSpace
+)
+
+func wordClassOf(r rune) wordClass {
+	switch r {
+	case '\r':
+		return wbCR
+	case '\n':
+		return wbLF
+	case 0x0B, 0x0C, 0x85, 0x2028, 0x2029:
+		return wbNewline
+	case 0x200D:
+		return wbExtend
+	case ':', 0x00B7, 0x0387, 0x05F4, 0x2027, 0xFE13, 0xFE55, 0xFF1A:
+		return wbMidLetter
+	case ',', ';', 0x037E, 0x0589, 0x060C, 0x060D, 0x066C, 0x07F8, 0x2044, 0xFE10, 0xFE14, 0xFE50, 0xFE54, 0xFF0C, 0xFF1B:
+		return wbMidNum
+	case '.', '\'', 0x2018, 0x2019, 0x2024, 0xFE52, 0xFF07, 0xFF0E:
+		return wbMidNumLet
+	case 0x30FC, 0x3031, 0x3032, 0x3033, 0x3034, 0x3035, 0x309B, 0x309C, 0x30A0, 0xFF70:
+		return wbKatakana
+	}
+	switch {
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc, unicode.Cf):
+		return wbExtend
+	case unicode.Is(unicode.Katakana, r):
+		return wbKatakana
+	case unicode.In(r, unicode.Han, unicode.Hiragana):
+		// 表意文字和平假名不属于 ALetter，相邻的字之间都是单词边界
+		return wbOther
+	case unicode.IsLetter(r):
+		return wbALetter
+	case unicode.Is(unicode.Nd, r):
+		return wbNumeric
+	case unicode.Is(unicode.Pc, r):
+		return wbExtendNumLet
+	case unicode.Is(unicode.Zs, r):
+		return wbSpace
+	}
+	return wbOther
+}
+
+// MaxExtend 是跳过附加字符（WB4）时最多跳过的个数，与 UAX #15 流安全格式对连续非起始字符的限制相同。
+// 更长的附加字符序列中，第 MaxExtend+1 个附加字符被当作 Other 类别的独立字符，
+// 因此 IsBoundary 只查看边界前后有限的数据。
+const MaxExtend = 30
+
+// Context 是 IsBoundary(s, i) 在 i 之前、之后各自最多查看的字节数：
+// 两侧各向外查看两个字符，每个字符最多带 MaxExtend 个附加字符。
+// 流式处理时保留这么多上下文，判断结果就与看到完整数据时相同。
+const Context = 2 * (MaxExtend + 1) * utf8.UTFMax
+
+// classBefore 返回 i 之前第一个不是 wbExtend 的字符的类别及其起始位置（WB4），
+// 最多跳过 MaxExtend 个附加字符。
+func classBefore(s []byte, i int) (wordClass, int) {
+	for n := 0; i > 0; n++ {
+		r, size := utf8.DecodeLastRune(s[:i])
+		i -= size
+		if c := wordClassOf(r); c != wbExtend {
+			return c, i
+		} else if n == MaxExtend {
+			return wbOther, i
+		}
+	}
+	return wbNone, 0
+}
+
+// classAfter 返回从 i 开始第一个不是 wbExtend 的字符的类别及其结束位置，
+// 最多跳过 MaxExtend 个附加字符。
+func classAfter(s []byte, i int) (wordClass, int) {
+	for n := 0; i < len(s); n++ {
+		r, size := utf8.DecodeRune(s[i:])
+		i += size
+		if c := wordClassOf(r); c != wbExtend {
+			return c, i
+		} else if n == MaxExtend {
+			return wbOther, i
+		}
+	}
+	return wbNone, len(s)
+}
+
+func isAHLetter(c wordClass) bool { return c == wbALetter }
+
+func isMidLetterish(c wordClass) bool { return c == wbMidLetter || c == wbMidNumLet }
+
+func isMidNumish(c wordClass) bool { return c == wbMidNum || c == wbMidNumLet }
+
+// IsBoundary 判断字符边界 i 是否是 UAX #29 定义的单词边界。
+func IsBoundary(s []byte, i int) bool {
+	if i == 0 || i == len(s) {
+		return true // WB1、WB2
+	}
+	prev, _ := utf8.DecodeLastRune(s[:i])
+	next, _ := utf8.DecodeRune(s[i:])
+	p, n := wordClassOf(prev), wordClassOf(next)
+	switch {
+	case p == wbCR && n == wbLF:
+		return false // WB3
+	case p == wbNewline || p == wbCR || p == wbLF || n == wbNewline || n == wbCR || n == wbLF:
+		return true // WB3a、WB3b
+	case p == wbSpace && n == wbSpace:
+		return false // WB3d
+	case n == wbExtend:
+		return false // WB4
+	}
+
+	// 之后的规则跳过附加在前一个字符上的组合符号
+	a, aStart := classBefore(s, i)
+	aa, _ := classBefore(s, aStart)
+	b, bEnd := classAfter(s, i)
+	bb, _ := classAfter(s, bEnd)
+	switch {
+	case isAHLetter(a) && isAHLetter(b):
+		return false // WB5
+	case isAHLetter(a) && isMidLetterish(b) && isAHLetter(bb):
+		return false // WB6
+	case isAHLetter(aa) && isMidLetterish(a) && isAHLetter(b):
+		return false // WB7
+	case a == wbNumeric && b == wbNumeric:
+		return false // WB8
+	case isAHLetter(a) && b == wbNumeric, a == wbNumeric && isAHLetter(b):
+		return false // WB9、WB10
+	case aa == wbNumeric && isMidNumish(a) && b == wbNumeric:
+		return false // WB11
+	case a == wbNumeric && isMidNumish(b) && bb == wbNumeric:
+		return false // WB12
+	case a == wbKatakana && b == wbKatakana:
+		return false // WB13
+	case b == wbExtendNumLet && (isAHLetter(a) || a == wbNumeric || a == wbKatakana || a == wbExtendNumLet):
+		return false // WB13a
+	case a == wbExtendNumLet && (isAHLetter(b) || b == wbNumeric || b == wbKatakana):
+		return false // WB13b
+	}
+	return true // WB999
+}
@@ -0,0 +1,92 @@
+package wordbreak
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// wordBoundaries 返回 s 中所有单词边界的位置
+func wordBoundaries(s string) []int {
+	var bounds []int
+	for i := 0; i <= len(s); {
+		if IsBoundary([]byte(s), i) {
+			bounds = append(bounds, i)
+		}
+		if i == len(s) {
+			break
+		}
+		_, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+	}
+	return bounds
+}
+
+// splitWords 按单词边界切分 s
+func splitWords(s string) []string {
+	var words []string
+	bounds := wordBoundaries(s)
+	for k := 1; k < len(bounds); k++ {
+		words = append(words, s[bounds[k-1]:bounds[k]])
+	}
+	return words
+}
+
+func TestIsWordBoundary(t *testing.T) {
+	// 期望结果取自 UAX #29 的 WordBreakTest.txt 中对应规则的示例
+	tests := []struct {
+		s    string
+		want []string
+	}{
+		{"", nil},
+		{"hello world", []string{"hello", " ", "world"}},
+		{"can't stop", []string{"can't", " ", "stop"}},
+		{"e.g. 3.14", []string{"e.g", ".", " ", "3.14"}},
+		{"1,000,000", []string{"1,000,000"}},
+		{"a1b2 go_lang", []string{"a1b2", " ", "go_lang"}},
+		{"end.", []string{"end", "."}},
+		{"a  b", []string{"a", "  ", "b"}},
+		{"x\r\ny", []string{"x", "\r\n", "y"}},
+		{"été", []string{"été"}},
+		{"日本語", []string{"日", "本", "語"}},
+		{"カタカナ", []string{"カタカナ"}},
+		{"ひらがな", []string{"ひ", "ら", "が", "な"}},
+		{"Ωmega's", []string{"Ωmega's"}},
+		{"a:b", []string{"a:b"}},
+		{"1:2", []string{"1", ":", "2"}},
+	}
+	for _, test := range tests {
+		got := splitWords(test.s)
+		if len(got) != len(test.want) {
+			t.Errorf("splitWords(%q) = %q, want %q", test.s, got, test.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != test.want[i] {
+				t.Errorf("splitWords(%q) = %q, want %q", test.s, got, test.want)
+				break
+			}
+		}
+	}
+}
+
+// IsBoundary 只查看边界两侧 Context 字节内的数据，截掉更远的部分结果不变
+func TestIsBoundaryContext(t *testing.T) {
+	marks := func(n int) string { return strings.Repeat("\u0301", n) }
+	for _, s := range []string{
+		"x" + marks(20) + "go go",
+		"x" + marks(5*MaxExtend) + "go",
+		"go'" + strings.Repeat("\u200d", 3*MaxExtend) + "s 1" + marks(MaxExtend) + ",2",
+		"日" + marks(2*MaxExtend) + "本\xff" + marks(MaxExtend+1) + "ab",
+	} {
+		for i := 0; i <= len(s); i++ {
+			if i < len(s) && !utf8.RuneStart(s[i]) {
+				continue
+			}
+			lo, hi := max(0, i-Context), min(len(s), i+Context)
+			if got, want := IsBoundary([]byte(s[lo:hi]), i-lo), IsBoundary([]byte(s), i); got != want {
+				t.Errorf("IsBoundary(%q, %d) = %v within context, %v on full text", s, i, got, want)
+			}
+		}
+	}
+}